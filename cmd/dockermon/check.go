@@ -0,0 +1,178 @@
+// cmd/dockermon/check.go
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rusenback/docker-monitor/internal/docker"
+	"github.com/rusenback/docker-monitor/internal/storage"
+)
+
+// checkReport prints the result of each check and remembers critical failures
+type checkReport struct {
+	failed bool
+}
+
+func (r *checkReport) pass(name, format string, args ...any) {
+	fmt.Printf("✅ %-18s %s\n", name, fmt.Sprintf(format, args...))
+}
+
+func (r *checkReport) warn(name, format string, args ...any) {
+	fmt.Printf("⚠️  %-18s %s\n", name, fmt.Sprintf(format, args...))
+}
+
+// fail records a critical failure
+func (r *checkReport) fail(name, format string, args ...any) {
+	r.failed = true
+	fmt.Printf("❌ %-18s %s\n", name, fmt.Sprintf(format, args...))
+}
+
+// runCheck verifies the environment dockermon needs and prints a report,
+// including configErr from loading the settings. It returns the process
+// exit code: 1 if a critical check failed.
+func runCheck(cfg docker.Config, configErr error) int {
+	r := &checkReport{}
+	fmt.Println("🔎 dockermon self-check")
+	fmt.Println()
+
+	if configErr != nil {
+		r.fail("Config", "%v", configErr)
+	} else {
+		r.pass("Config", "settings loaded")
+	}
+	checkSocket(r, cfg.Host)
+
+	client, err := docker.NewClient(cfg)
+	if err != nil {
+		r.fail("Docker", "not reachable at %s: %v", cfg.Host, err)
+	} else {
+		defer client.Close()
+		r.pass("Docker", "reachable at %s", cfg.Host)
+		checkDaemon(r, client)
+	}
+
+	checkStorage(r)
+
+	if client != nil {
+		checkSampleContainer(r, client)
+	}
+
+	fmt.Println()
+	if r.failed {
+		fmt.Println("Some critical checks failed.")
+		return 1
+	}
+	fmt.Println("All critical checks passed.")
+	return 0
+}
+
+// checkSocket reports the permissions of a local Docker socket
+func checkSocket(r *checkReport, host string) {
+	path, ok := strings.CutPrefix(host, "unix://")
+	if !ok {
+		r.pass("Socket", "not a unix socket (%s), skipped", host)
+		return
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		r.fail("Socket", "%v", err)
+		return
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		r.fail("Socket", "%s is not a socket (%s)", path, info.Mode())
+		return
+	}
+
+	if err := checkSocketAccess(path); err != nil {
+		r.fail("Socket", "%s (%s): no read/write access: %v", path, info.Mode(), err)
+		return
+	}
+	r.pass("Socket", "%s (%s)", path, info.Mode())
+}
+
+// checkDaemon reports the daemon version, negotiated API version and cgroup version
+func checkDaemon(r *checkReport, client *docker.Client) {
+	info, err := client.DaemonInfo()
+	if err != nil {
+		r.fail("Version", "%v", err)
+		return
+	}
+
+	r.pass("Version", "Docker %s (%s/%s, kernel %s)", info.ServerVersion, info.OS, info.Arch, info.KernelVersion)
+	r.pass("API", "using %s (daemon supports %s to %s)", info.ClientAPI, info.MinAPIVersion, info.APIVersion)
+
+	switch info.CgroupVersion {
+	case "":
+		r.warn("Cgroups", "version not reported by the daemon")
+	case "1":
+		r.pass("Cgroups", "v1 (%s driver)", info.CgroupDriver)
+	default:
+		r.pass("Cgroups", "v%s (%s driver)", info.CgroupVersion, info.CgroupDriver)
+	}
+}
+
+// checkStorage verifies that the stats history can be written, without creating it
+func checkStorage(r *checkReport) {
+	path, err := storage.CheckWritable()
+	if err != nil {
+		r.fail("Storage", "%v", err)
+		return
+	}
+	r.pass("Storage", "%s is writable", path)
+}
+
+// checkSampleContainer tries process listing and stats streaming on the first running container
+func checkSampleContainer(r *checkReport, client *docker.Client) {
+	containers, err := client.ListContainers()
+	if err != nil {
+		r.fail("Containers", "%v", err)
+		return
+	}
+
+	var id, name string
+	for _, c := range containers {
+		if c.State == "running" {
+			id, name = c.ID, c.Name
+			break
+		}
+	}
+	if id == "" {
+		r.pass("Containers", "%d found", len(containers))
+		r.warn("Processes", "skipped, no running container to sample")
+		r.warn("Stats stream", "skipped, no running container to sample")
+		return
+	}
+	r.pass("Containers", "%d found, sampling %s", len(containers), name)
+
+	processes, err := client.GetContainerProcesses(id)
+	if err != nil {
+		r.fail("Processes", "ContainerTop failed: %v", err)
+	} else {
+		r.pass("Processes", "ContainerTop returned %d processes", len(processes))
+	}
+
+	statsChan, errChan, cancel := client.StreamContainerStats(id)
+	defer cancel()
+
+	select {
+	case stats, ok := <-statsChan:
+		if !ok {
+			r.fail("Stats stream", "closed before the first sample")
+			return
+		}
+		r.pass("Stats stream", "CPU %.1f%% of %d cores, memory %.1f%%",
+			stats.CPUPercent, stats.OnlineCPUs, stats.MemoryPercent)
+	case err, ok := <-errChan:
+		if !ok {
+			r.fail("Stats stream", "closed before the first sample")
+			return
+		}
+		r.fail("Stats stream", "%v", err)
+	case <-time.After(10 * time.Second):
+		r.fail("Stats stream", "no sample within 10 seconds")
+	}
+}
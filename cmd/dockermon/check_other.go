@@ -0,0 +1,19 @@
+//go:build !unix
+
+// cmd/dockermon/check_other.go
+package main
+
+import (
+	"net"
+	"time"
+)
+
+// checkSocketAccess verifies that the socket accepts a connection, as
+// there is no access(2) to ask for read and write permission
+func checkSocketAccess(path string) error {
+	conn, err := net.DialTimeout("unix", path, 2*time.Second)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
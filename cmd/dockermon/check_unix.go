@@ -0,0 +1,11 @@
+//go:build unix
+
+// cmd/dockermon/check_unix.go
+package main
+
+import "golang.org/x/sys/unix"
+
+// checkSocketAccess verifies that the socket can be read and written, as connecting needs both
+func checkSocketAccess(path string) error {
+	return unix.Access(path, unix.R_OK|unix.W_OK)
+}
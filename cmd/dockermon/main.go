@@ -2,18 +2,35 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rusenback/docker-monitor/internal/config"
 	"github.com/rusenback/docker-monitor/internal/docker"
 	"github.com/rusenback/docker-monitor/internal/storage"
 	"github.com/rusenback/docker-monitor/internal/tui"
 )
 
 func main() {
+	check := flag.Bool("check", false, "check the environment and print a diagnostic report")
+	processes := flag.Int("processes", 0, "number of processes listed in the stats panel (overrides process_limit)")
+	flag.Parse()
+
+	// Load user settings
+	settings, err := config.Load()
+	if *check {
+		// The check reports a broken config alongside everything else
+		os.Exit(runCheck(dockerConfig(settings, *processes), err))
+	}
+	if err != nil {
+		fmt.Printf("❌ Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+	cfg := dockerConfig(settings, *processes)
+
 	// Create Docker client
-	cfg := docker.DefaultConfig()
 	client, err := docker.NewClient(cfg)
 	if err != nil {
 		fmt.Printf("❌ Failed to connect to Docker: %v\n", err)
@@ -33,7 +50,7 @@ func main() {
 	defer store.Close()
 
 	// Create TUI model
-	m := tui.NewModel(client, store)
+	m := tui.NewModel(client, store, settings)
 
 	// Start TUI
 	p := tea.NewProgram(m, tea.WithAltScreen())
@@ -42,3 +59,19 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// dockerConfig applies the user settings, if they loaded, and the
+// --processes flag to the default client config
+func dockerConfig(settings *config.Config, processes int) docker.Config {
+	cfg := docker.DefaultConfig()
+	if settings != nil {
+		if settings.ProcessLimit > 0 {
+			cfg.ProcessLimit = settings.ProcessLimit
+		}
+		cfg.ListSizes = settings.ContainerSizes
+	}
+	if processes > 0 {
+		cfg.ProcessLimit = processes
+	}
+	return cfg
+}
@@ -0,0 +1,252 @@
+// Package config handles user settings that persist between sessions
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Config holds user settings stored in ~/.dockermon/config.json
+type Config struct {
+	// Tags maps container names to user-assigned tags. Names are used rather
+	// than IDs so tags reattach when a container is recreated.
+	Tags map[string][]string `json:"tags,omitempty"`
+
+	// Alerts sets the usage levels recorded as limit breaches
+	Alerts Thresholds `json:"alerts,omitzero"`
+
+	// Self controls how the monitor's own container is listed when it runs
+	// containerized: "mark" (default), "hide" or "off" to disable detection
+	Self string `json:"self,omitempty"`
+
+	// CPUUnit is how CPU usage is shown: "percent" (default) or "cores"
+	CPUUnit string `json:"cpu_unit,omitempty"`
+
+	// Theme selects the color scheme: "default" or "colorblind"
+	Theme string `json:"theme,omitempty"`
+
+	// GraphColors overrides individual graph colors and characters of the theme
+	GraphColors GraphColors `json:"graph_colors,omitzero"`
+
+	// Scanner is an optional vulnerability scanner command producing Trivy or
+	// Grype JSON, e.g. "trivy image --format json --quiet {image}"
+	Scanner string `json:"scanner,omitempty"`
+
+	// Pins lists the names of containers shown in the comparison view, in
+	// the order they were pinned
+	Pins []string `json:"pins,omitempty"`
+
+	// ServiceNames shows Compose and swarm containers as "service.replica"
+	// instead of their generated names
+	ServiceNames bool `json:"service_names,omitempty"`
+
+	// ProcessLimit is how many processes the stats panel lists (default 10)
+	ProcessLimit int `json:"process_limit,omitempty"`
+
+	// ContainerSizes asks Docker for container filesystem sizes, shown in the
+	// inspect view. This is slow with many or large containers.
+	ContainerSizes bool `json:"container_sizes,omitempty"`
+
+	path string
+}
+
+// GraphColors holds graph colors (lipgloss color strings such as "#0072B2")
+// and the characters drawn for each series
+type GraphColors struct {
+	CPU         string `json:"cpu,omitempty"`
+	Memory      string `json:"memory,omitempty"`
+	Overlap     string `json:"overlap,omitempty"`
+	CPUChar     string `json:"cpu_char,omitempty"`
+	MemoryChar  string `json:"memory_char,omitempty"`
+	OverlapChar string `json:"overlap_char,omitempty"`
+}
+
+// Theme names
+const (
+	ThemeDefault    = "default"
+	ThemeColorblind = "colorblind"
+)
+
+// CPU display units
+const (
+	CPUPercent = "percent"
+	CPUCores   = "cores"
+)
+
+// Self-exclusion modes
+const (
+	SelfMark = "mark"
+	SelfHide = "hide"
+	SelfOff  = "off"
+)
+
+// SelfMode returns how the monitor's own container is handled
+func (c *Config) SelfMode() string {
+	switch c.Self {
+	case SelfHide, SelfOff:
+		return c.Self
+	default:
+		return SelfMark
+	}
+}
+
+// Thresholds holds warning levels in percent of a container's limit
+type Thresholds struct {
+	CPUPercent    float64 `json:"cpu_percent,omitempty"`
+	MemoryPercent float64 `json:"memory_percent,omitempty"`
+}
+
+// DefaultThresholds matches the level where the stats panel turns red
+var DefaultThresholds = Thresholds{CPUPercent: 80, MemoryPercent: 80}
+
+// AlertThresholds returns the configured thresholds, falling back to the defaults
+func (c *Config) AlertThresholds() Thresholds {
+	t := c.Alerts
+	if t.CPUPercent <= 0 {
+		t.CPUPercent = DefaultThresholds.CPUPercent
+	}
+	if t.MemoryPercent <= 0 {
+		t.MemoryPercent = DefaultThresholds.MemoryPercent
+	}
+	return t
+}
+
+// Load reads the config file, returning an empty config if it does not exist yet
+func Load() (*Config, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	return LoadFrom(filepath.Join(homeDir, ".dockermon", "config.json"))
+}
+
+// LoadFrom reads the config file at the given path
+func LoadFrom(path string) (*Config, error) {
+	cfg := &Config{path: path}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Save writes the config file, replacing it atomically
+func (c *Config) Save() error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+
+	return os.Rename(tmp, c.path)
+}
+
+// TagsFor returns the tags assigned to a container
+func (c *Config) TagsFor(name string) []string {
+	return c.Tags[name]
+}
+
+// SetTags replaces the tags of a container; an empty list removes them
+func (c *Config) SetTags(name string, tags []string) {
+	if len(tags) == 0 {
+		delete(c.Tags, name)
+		return
+	}
+	if c.Tags == nil {
+		c.Tags = make(map[string][]string)
+	}
+	c.Tags[name] = tags
+}
+
+// HasTag reports whether a container carries the given tag
+func (c *Config) HasTag(name, tag string) bool {
+	for _, t := range c.Tags[name] {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// AllTags returns every tag in use, sorted
+func (c *Config) AllTags() []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, containerTags := range c.Tags {
+		for _, t := range containerTags {
+			if !seen[t] {
+				seen[t] = true
+				tags = append(tags, t)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// IsPinned reports whether a container is pinned for comparison
+func (c *Config) IsPinned(name string) bool {
+	for _, p := range c.Pins {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// TogglePin pins or unpins a container and reports whether it is now pinned
+func (c *Config) TogglePin(name string) bool {
+	for i, p := range c.Pins {
+		if p == name {
+			c.Pins = append(c.Pins[:i], c.Pins[i+1:]...)
+			return false
+		}
+	}
+	c.Pins = append(c.Pins, name)
+	return true
+}
+
+// ClearPins unpins all containers
+func (c *Config) ClearPins() {
+	c.Pins = nil
+}
+
+// ParseTags splits user input such as "critical, db web" into unique tags
+func ParseTags(input string) []string {
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+
+	seen := make(map[string]bool)
+	tags := make([]string, 0, len(fields))
+	for _, f := range fields {
+		f = strings.TrimPrefix(f, "#")
+		if f != "" && !seen[f] {
+			seen[f] = true
+			tags = append(tags, f)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"", []string{}},
+		{"critical", []string{"critical"}},
+		{"critical, db web", []string{"critical", "db", "web"}},
+		{"#db,,#db  web,", []string{"db", "web"}},
+		{" , # ", []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := ParseTags(tt.input); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("ParseTags(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestTags(t *testing.T) {
+	cfg := &Config{}
+	if tags := cfg.AllTags(); len(tags) != 0 {
+		t.Errorf("AllTags() = %q, want none", tags)
+	}
+
+	cfg.SetTags("web", []string{"prod", "frontend"})
+	cfg.SetTags("db", []string{"prod", "critical"})
+	if !cfg.HasTag("web", "frontend") || cfg.HasTag("db", "frontend") || cfg.HasTag("cache", "prod") {
+		t.Error("HasTag does not match the tags that were set")
+	}
+	if got := fmt.Sprint(cfg.AllTags()); got != "[critical frontend prod]" {
+		t.Errorf("AllTags() = %s, want [critical frontend prod]", got)
+	}
+
+	// An empty list removes the container's tags
+	cfg.SetTags("web", nil)
+	if _, ok := cfg.Tags["web"]; ok {
+		t.Errorf("tags of web = %q, want them removed", cfg.TagsFor("web"))
+	}
+	if got := fmt.Sprint(cfg.AllTags()); got != "[critical prod]" {
+		t.Errorf("AllTags() = %s, want [critical prod]", got)
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".dockermon", "config.json")
+
+	// A missing file is an empty config
+	cfg, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	if fmt.Sprintf("%+v", *cfg) != fmt.Sprintf("%+v", Config{path: path}) {
+		t.Errorf("config = %+v, want it empty", *cfg)
+	}
+
+	cfg.SetTags("web", []string{"prod"})
+	cfg.Alerts = Thresholds{CPUPercent: 90}
+	cfg.Theme = ThemeColorblind
+	cfg.Pins = []string{"web", "db"}
+	cfg.ProcessLimit = 25
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+
+	loaded, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	if fmt.Sprintf("%+v", *loaded) != fmt.Sprintf("%+v", *cfg) {
+		t.Errorf("loaded %+v, want %+v", *loaded, *cfg)
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFrom(path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("err = %v, want a parse error naming the file", err)
+	}
+}
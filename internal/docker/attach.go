@@ -0,0 +1,43 @@
+// internal/docker/attach.go
+package docker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ErrStdinClosed is returned when a container was not started with an open stdin
+var ErrStdinClosed = errors.New("container does not accept input (start it with -i or stdin_open: true)")
+
+// AttachStdin attaches to the stdin of a container's main process. Output is
+// not attached; it arrives through the log stream like any other output.
+// Closing the returned writer detaches. The container's stdin stays open,
+// unless the container was created with StdinOnce (e.g. "docker run -i"
+// without -d), in which case Docker closes it and the process sees end of input.
+func (c *Client) AttachStdin(id string) (io.WriteCloser, error) {
+	info, err := c.GetContainerRunInfo(id)
+	if err != nil {
+		return nil, err
+	}
+	if !info.OpenStdin {
+		return nil, ErrStdinClosed
+	}
+
+	// The timeout covers setting up the attachment; the connection outlives it
+	ctx, cancel := context.WithTimeout(c.Ctx, 10*time.Second)
+	defer cancel()
+
+	resp, err := c.cli.ContainerAttach(ctx, id, container.AttachOptions{
+		Stream: true,
+		Stdin:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Conn, nil
+}
@@ -2,9 +2,11 @@ package docker
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/docker/docker/client"
+	"github.com/rusenback/docker-monitor/internal/model"
 )
 
 // Config contains Docker client configuration
@@ -13,19 +15,29 @@ type Config struct {
 	TLSVerify bool
 	CertPath  string
 	Timeout   time.Duration
+
+	// ProcessLimit caps how many processes are reported per container
+	ProcessLimit int
+
+	// ListSizes requests container filesystem sizes when listing containers.
+	// This is slow on hosts with many or large containers.
+	ListSizes bool
 }
 
 func DefaultConfig() Config {
 	return Config{
-		Host:    "unix:///var/run/docker.sock",
-		Timeout: 30 * time.Second,
+		Host:         "unix:///var/run/docker.sock",
+		Timeout:      30 * time.Second,
+		ProcessLimit: 10,
 	}
 }
 
 // Client wraps the Docker API client
 type Client struct {
-	cli *client.Client
-	Ctx context.Context
+	cli          *client.Client
+	Ctx          context.Context
+	processLimit int
+	listSizes    bool
 }
 
 // NewClient creates a new Docker client
@@ -57,12 +69,43 @@ func NewClient(cfg Config) (*Client, error) {
 	}
 
 	return &Client{
-		cli: cli,
-		Ctx: context.Background(),
+		cli:          cli,
+		Ctx:          context.Background(),
+		processLimit: cfg.ProcessLimit,
+		listSizes:    cfg.ListSizes,
 	}, nil
 
 }
 
+// DaemonInfo returns the daemon version, the negotiated API version and the
+// cgroup setup of the host
+func (c *Client) DaemonInfo() (*model.DaemonInfo, error) {
+	ctx, cancel := context.WithTimeout(c.Ctx, 5*time.Second)
+	defer cancel()
+
+	version, err := c.cli.ServerVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daemon version: %w", err)
+	}
+
+	info, err := c.cli.Info(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daemon info: %w", err)
+	}
+
+	return &model.DaemonInfo{
+		ServerVersion: version.Version,
+		APIVersion:    version.APIVersion,
+		MinAPIVersion: version.MinAPIVersion,
+		ClientAPI:     c.cli.ClientVersion(),
+		OS:            version.Os,
+		Arch:          version.Arch,
+		KernelVersion: version.KernelVersion,
+		CgroupVersion: info.CgroupVersion,
+		CgroupDriver:  info.CgroupDriver,
+	}, nil
+}
+
 // Close closes the connection
 func (c *Client) Close() error {
 	if c.cli != nil {
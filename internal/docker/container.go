@@ -3,9 +3,12 @@ package docker
 
 import (
 	"context"
+	"errors"
+	"net"
 	"strings"
 	"time"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/rusenback/docker-monitor/internal/model"
 )
@@ -13,7 +16,8 @@ import (
 // ListContainers returns all containers (running + stopped)
 func (c *Client) ListContainers() ([]model.Container, error) {
 	containers, err := c.cli.ContainerList(c.Ctx, container.ListOptions{
-		All: true, // Show stopped containers too
+		All:  true,        // Show stopped containers too
+		Size: c.listSizes, // Sizes make the daemon walk each container's filesystem
 	})
 	if err != nil {
 		return nil, err
@@ -21,34 +25,49 @@ func (c *Client) ListContainers() ([]model.Container, error) {
 
 	result := make([]model.Container, 0, len(containers))
 	for _, cont := range containers {
-		// Remove "/" from container name if present
-		name := cont.Names[0]
-		if strings.HasPrefix(name, "/") {
-			name = name[1:]
-		}
+		result = append(result, toContainer(cont))
+	}
 
-		// Convert ports
-		ports := make([]model.Port, 0)
-		for _, p := range cont.Ports {
-			ports = append(ports, model.Port{
-				Private: int(p.PrivatePort),
-				Public:  int(p.PublicPort),
-				Type:    p.Type,
-			})
-		}
+	return result, nil
+}
 
-		result = append(result, model.Container{
-			ID:      cont.ID[:12], // Short ID
-			Name:    name,
-			Image:   cont.Image,
-			Status:  cont.Status,
-			State:   cont.State,
-			Created: time.Unix(cont.Created, 0),
-			Ports:   ports,
+// toContainer maps a container list summary to model.Container, keeping the
+// data the summary already carries so it needs no extra inspect call
+func toContainer(cont types.Container) model.Container {
+	// Remove "/" from container name if present
+	name := ""
+	if len(cont.Names) > 0 {
+		name = strings.TrimPrefix(cont.Names[0], "/")
+	}
+
+	// Convert ports
+	ports := make([]model.Port, 0)
+	for _, p := range cont.Ports {
+		ports = append(ports, model.Port{
+			Private: int(p.PrivatePort),
+			Public:  int(p.PublicPort),
+			Type:    p.Type,
 		})
 	}
 
-	return result, nil
+	result := model.Container{
+		ID:         cont.ID[:12], // Short ID
+		Name:       name,
+		Image:      cont.Image,
+		ImageID:    cont.ImageID,
+		Command:    cont.Command,
+		Status:     cont.Status,
+		State:      cont.State,
+		Created:    time.Unix(cont.Created, 0),
+		Ports:      ports,
+		Labels:     cont.Labels,
+		SizeRw:     cont.SizeRw,
+		SizeRootFs: cont.SizeRootFs,
+	}
+	result.ServiceName = serviceName(result.Name, result.Labels)
+	result.Project = projectName(result.Labels)
+
+	return result
 }
 
 // StartContainer starts a container
@@ -80,3 +99,63 @@ func (c *Client) RestartContainer(id string) error {
 		Timeout: &timeout,
 	})
 }
+
+// KillContainer force-kills a container that did not stop gracefully
+func (c *Client) KillContainer(id string) error {
+	Ctx, cancel := context.WithTimeout(c.Ctx, 10*time.Second)
+	defer cancel()
+
+	return c.cli.ContainerKill(Ctx, id, "SIGKILL")
+}
+
+// IsTimeout reports whether an error is the result of an operation timing out
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// GetContainerRunInfo inspects a container and returns details about its current run
+func (c *Client) GetContainerRunInfo(id string) (*model.RunInfo, error) {
+	ctx, cancel := context.WithTimeout(c.Ctx, 5*time.Second)
+	defer cancel()
+
+	info, err := c.cli.ContainerInspect(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.RunInfo{
+		RestartCount: info.RestartCount,
+		LogPath:      info.LogPath,
+	}
+
+	if info.State != nil {
+		result.State = info.State.Status
+		result.Running = info.State.Running
+		// Docker reports zero times as "0001-01-01T00:00:00Z", which parses to the zero value
+		result.StartedAt, _ = time.Parse(time.RFC3339Nano, info.State.StartedAt)
+		result.FinishedAt, _ = time.Parse(time.RFC3339Nano, info.State.FinishedAt)
+	}
+
+	if info.Config != nil {
+		result.OpenStdin = info.Config.OpenStdin
+		result.StdinOnce = info.Config.StdinOnce
+	}
+
+	if info.HostConfig != nil {
+		result.LogDriver = info.HostConfig.LogConfig.Type
+
+		// --cpus sets NanoCPUs, older options set a CFS quota per period
+		switch {
+		case info.HostConfig.NanoCPUs > 0:
+			result.CPULimit = float64(info.HostConfig.NanoCPUs) / 1e9
+		case info.HostConfig.CPUQuota > 0 && info.HostConfig.CPUPeriod > 0:
+			result.CPULimit = float64(info.HostConfig.CPUQuota) / float64(info.HostConfig.CPUPeriod)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,80 @@
+package docker
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/rusenback/docker-monitor/internal/model"
+)
+
+func TestToContainer(t *testing.T) {
+	const id = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		summary types.Container
+		want    model.Container
+	}{
+		{
+			name: "plain container without sizes",
+			summary: types.Container{
+				ID: id, Names: []string{"/redis"}, Image: "redis:7", ImageID: "sha256:abc",
+				Command: "docker-entrypoint.sh redis-server", State: "running", Status: "Up 2 hours",
+				Created: created.Unix(),
+				Ports:   []types.Port{{PrivatePort: 6379, PublicPort: 6380, Type: "tcp"}},
+			},
+			want: model.Container{
+				ID: id[:12], Name: "redis", Image: "redis:7", ImageID: "sha256:abc",
+				Command: "docker-entrypoint.sh redis-server", State: "running", Status: "Up 2 hours",
+				Created: created,
+				Ports:   []model.Port{{Private: 6379, Public: 6380, Type: "tcp"}},
+			},
+		},
+		{
+			name: "compose service with sizes",
+			summary: types.Container{
+				ID: id, Names: []string{"/shop-web-2"}, Image: "shop-web", Command: "npm start",
+				State: "exited", Created: created.Unix(), SizeRw: 4096, SizeRootFs: 1 << 20,
+				Labels: map[string]string{composeProjectLabel: "shop", composeServiceLabel: "web", "tier": "frontend"},
+			},
+			want: model.Container{
+				ID: id[:12], Name: "shop-web-2", Image: "shop-web", Command: "npm start",
+				State: "exited", Created: created, Ports: []model.Port{}, SizeRw: 4096, SizeRootFs: 1 << 20,
+				Labels:      map[string]string{composeProjectLabel: "shop", composeServiceLabel: "web", "tier": "frontend"},
+				ServiceName: "web.2", Project: "shop",
+			},
+		},
+		{
+			name: "swarm task",
+			summary: types.Container{
+				ID: id, Names: []string{"/mystack_api.3.q3o5ld6lq8pc"}, Image: "api:1", State: "running",
+				Created: created.Unix(),
+				Labels:  map[string]string{stackNamespaceLabel: "mystack", swarmServiceLabel: "mystack_api", swarmSlotLabel: "3"},
+			},
+			want: model.Container{
+				ID: id[:12], Name: "mystack_api.3.q3o5ld6lq8pc", Image: "api:1", State: "running",
+				Created: created, Ports: []model.Port{},
+				Labels:      map[string]string{stackNamespaceLabel: "mystack", swarmServiceLabel: "mystack_api", swarmSlotLabel: "3"},
+				ServiceName: "api.3", Project: "mystack",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := toContainer(tt.summary)
+			if !got.Created.Equal(tt.want.Created) {
+				t.Errorf("Created = %v, want %v", got.Created, tt.want.Created)
+			}
+			got.Created, tt.want.Created = time.Time{}, time.Time{}
+
+			// Maps and slices are compared through their printed form
+			if fmt.Sprintf("%+v", got) != fmt.Sprintf("%+v", tt.want) {
+				t.Errorf("toContainer() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,12 @@
 // internal/docker/interface.go
 package docker
 
-import "github.com/rusenback/docker-monitor/internal/model"
+import (
+	"io"
+	"time"
+
+	"github.com/rusenback/docker-monitor/internal/model"
+)
 
 // DockerClient interface allows mocking in tests
 type DockerClient interface {
@@ -9,11 +14,17 @@ type DockerClient interface {
 	StartContainer(id string) error
 	StopContainer(id string) error
 	RestartContainer(id string) error
+	KillContainer(id string) error
+	GetContainerRunInfo(id string) (*model.RunInfo, error)
 	GetContainerStats(id string) (*model.Stats, error)
 	StreamContainerStats(id string) (<-chan *model.Stats, <-chan error, func())
 
 	GetContainerLogs(id string, tail int) ([]model.LogEntry, error)
 	StreamContainerLogs(id string) (<-chan model.LogEntry, <-chan error, func())
+	StreamContainerLogsSince(id string, since time.Time) (<-chan model.LogEntry, <-chan error, func())
+	GetArchivedLogs(id string) ([]model.LogEntry, error)
+
+	AttachStdin(id string) (io.WriteCloser, error)
 
 	Close() error
 }
@@ -3,8 +3,13 @@ package docker
 
 import (
 	"bufio"
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -13,6 +18,9 @@ import (
 	"github.com/rusenback/docker-monitor/internal/model"
 )
 
+// ErrArchivedLogsUnavailable is returned when rotated log files cannot be read
+var ErrArchivedLogsUnavailable = errors.New("archived logs unavailable")
+
 // GetContainerLogs retrieves container logs
 func (c *Client) GetContainerLogs(id string, tail int) ([]model.LogEntry, error) {
 	ctx, cancel := context.WithTimeout(c.Ctx, 5*time.Second)
@@ -36,6 +44,32 @@ func (c *Client) GetContainerLogs(id string, tail int) ([]model.LogEntry, error)
 
 // StreamContainerLogs streams container logs in real-time
 func (c *Client) StreamContainerLogs(id string) (<-chan model.LogEntry, <-chan error, func()) {
+	return c.streamLogs(id, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Timestamps: true,
+		Follow:     true, // Stream logs continuously
+		Tail:       "10", // Start with last 10 lines
+	})
+}
+
+// StreamContainerLogsSince streams container logs written after the given time.
+// It is used to resume following a container after its previous stream ended.
+func (c *Client) StreamContainerLogsSince(id string, since time.Time) (<-chan model.LogEntry, <-chan error, func()) {
+	// Docker treats "since" as inclusive, so skip past the last entry we already have
+	since = since.Add(time.Nanosecond)
+
+	return c.streamLogs(id, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Timestamps: true,
+		Follow:     true,
+		Since:      fmt.Sprintf("%d.%09d", since.Unix(), since.Nanosecond()),
+	})
+}
+
+// streamLogs follows the container log stream described by options
+func (c *Client) streamLogs(id string, options container.LogsOptions) (<-chan model.LogEntry, <-chan error, func()) {
 	logsChan := make(chan model.LogEntry)
 	errChan := make(chan error, 1)
 
@@ -45,14 +79,6 @@ func (c *Client) StreamContainerLogs(id string) (<-chan model.LogEntry, <-chan e
 		defer close(logsChan)
 		defer close(errChan)
 
-		options := container.LogsOptions{
-			ShowStdout: true,
-			ShowStderr: true,
-			Timestamps: true,
-			Follow:     true, // Stream logs continuously
-			Tail:       "10", // Start with last 10 lines
-		}
-
 		reader, err := c.cli.ContainerLogs(ctx, id, options)
 		if err != nil {
 			errChan <- err
@@ -87,6 +113,106 @@ func (c *Client) StreamContainerLogs(id string) (<-chan model.LogEntry, <-chan e
 	return logsChan, errChan, cancel
 }
 
+// GetArchivedLogs reads log files rotated away by the json-file driver.
+// Entries are returned oldest first. The files live under Docker's data
+// directory, so they are usually only readable when running as root.
+func (c *Client) GetArchivedLogs(id string) ([]model.LogEntry, error) {
+	info, err := c.GetContainerRunInfo(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.LogDriver != "json-file" || info.LogPath == "" {
+		return nil, fmt.Errorf("%w: log driver %q keeps no readable files", ErrArchivedLogsUnavailable, info.LogDriver)
+	}
+
+	// Rotated files are named <path>.1, <path>.2, ... with .1 being the newest.
+	// They are gzipped when the driver's "compress" option is enabled.
+	var files []string
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("%s.%d", info.LogPath, i)
+		if _, err := os.Stat(name); err == nil {
+			files = append(files, name)
+			continue
+		} else if os.IsPermission(err) {
+			return nil, fmt.Errorf("%w: %v", ErrArchivedLogsUnavailable, err)
+		}
+		if _, err := os.Stat(name + ".gz"); err == nil {
+			files = append(files, name+".gz")
+			continue
+		} else if os.IsPermission(err) {
+			return nil, fmt.Errorf("%w: %v", ErrArchivedLogsUnavailable, err)
+		}
+		break
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: no rotated log files", ErrArchivedLogsUnavailable)
+	}
+
+	var entries []model.LogEntry
+	for i := len(files) - 1; i >= 0; i-- {
+		fileEntries, err := readJSONLogFile(files[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrArchivedLogsUnavailable, err)
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	return entries, nil
+}
+
+// jsonLogLine is a single line of a json-file driver log file
+type jsonLogLine struct {
+	Log    string    `json:"log"`
+	Stream string    `json:"stream"`
+	Time   time.Time `json:"time"`
+}
+
+// readJSONLogFile parses a (possibly gzipped) json-file driver log file
+func readJSONLogFile(path string) ([]model.LogEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var reader io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	var entries []model.LogEntry
+	decoder := json.NewDecoder(reader)
+	for {
+		var line jsonLogLine
+		if err := decoder.Decode(&line); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return entries, err
+		}
+
+		message := strings.TrimSpace(line.Log)
+		if message == "" {
+			continue
+		}
+
+		entries = append(entries, model.LogEntry{
+			Timestamp: line.Time,
+			Message:   message,
+			Stream:    line.Stream,
+		})
+	}
+
+	return entries, nil
+}
+
 // parseLogStream parses a log stream into a slice of LogEntry
 func parseLogStream(reader io.Reader) ([]model.LogEntry, error) {
 	var entries []model.LogEntry
@@ -0,0 +1,81 @@
+// internal/docker/naming.go
+package docker
+
+import "strings"
+
+// Labels set by Docker Compose and swarm mode
+const (
+	composeProjectLabel = "com.docker.compose.project"
+	composeServiceLabel = "com.docker.compose.service"
+	composeNumberLabel  = "com.docker.compose.container-number"
+	swarmServiceLabel   = "com.docker.swarm.service.name"
+	swarmSlotLabel      = "com.docker.swarm.task.slot"
+	stackNamespaceLabel = "com.docker.stack.namespace"
+)
+
+// serviceName derives a "service.replica" name for containers started by
+// Compose or swarm, e.g. "web.1" for "myproject-web-1" (Compose v2) or
+// "myproject_web_1" (Compose v1). It returns "" for other containers.
+func serviceName(name string, labels map[string]string) string {
+	service := labels[composeServiceLabel]
+	if service == "" {
+		service = labels[swarmServiceLabel]
+		// Swarm service names carry the stack name, e.g. "mystack_web"
+		if ns := labels[stackNamespaceLabel]; ns != "" {
+			service = strings.TrimPrefix(service, ns+"_")
+		}
+	}
+	if service == "" {
+		return ""
+	}
+
+	replica := labels[swarmSlotLabel]
+	if replica == "" {
+		replica = labels[composeNumberLabel]
+	}
+	if replica == "" {
+		replica = replicaSuffix(name)
+	}
+
+	if replica == "" {
+		return service
+	}
+	return service + "." + replica
+}
+
+// projectName returns the Compose project or swarm stack a container belongs
+// to, or "" when it has neither
+func projectName(labels map[string]string) string {
+	if project := labels[composeProjectLabel]; project != "" {
+		return project
+	}
+	return labels[stackNamespaceLabel]
+}
+
+// replicaSuffix returns the replica number at the end of a generated
+// container name: "_1" (Compose v1), "-1" (Compose v2) or the ".1." slot of a
+// swarm task name such as "mystack_web.1.q3o5ld6lq8pc"
+func replicaSuffix(name string) string {
+	if parts := strings.Split(name, "."); len(parts) == 3 && isNumber(parts[1]) {
+		return parts[1]
+	}
+
+	i := strings.LastIndexAny(name, "_-")
+	if i < 0 || !isNumber(name[i+1:]) {
+		return ""
+	}
+	return name[i+1:]
+}
+
+// isNumber reports whether s is a non-empty run of digits
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
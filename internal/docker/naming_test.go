@@ -0,0 +1,108 @@
+package docker
+
+import "testing"
+
+func TestServiceName(t *testing.T) {
+	tests := []struct {
+		name      string
+		container string
+		labels    map[string]string
+		want      string
+	}{
+		{
+			name:      "compose v1",
+			container: "proj_web_1",
+			labels:    map[string]string{composeProjectLabel: "proj", composeServiceLabel: "web"},
+			want:      "web.1",
+		},
+		{
+			name:      "compose v2",
+			container: "proj-web-1",
+			labels:    map[string]string{composeProjectLabel: "proj", composeServiceLabel: "web"},
+			want:      "web.1",
+		},
+		{
+			// The container-number label wins over the generated name
+			name:      "compose number label",
+			container: "custom-name",
+			labels:    map[string]string{composeServiceLabel: "web", composeNumberLabel: "3"},
+			want:      "web.3",
+		},
+		{
+			name:      "compose without replica",
+			container: "my-api",
+			labels:    map[string]string{composeServiceLabel: "api"},
+			want:      "api",
+		},
+		{
+			name:      "swarm",
+			container: "mystack_web.2.q3o5ld6lq8pc",
+			labels: map[string]string{
+				swarmServiceLabel:   "mystack_web",
+				stackNamespaceLabel: "mystack",
+				swarmSlotLabel:      "2",
+			},
+			want: "web.2",
+		},
+		{
+			name:      "swarm without slot label",
+			container: "mystack_web.2.q3o5ld6lq8pc",
+			labels:    map[string]string{swarmServiceLabel: "mystack_web", stackNamespaceLabel: "mystack"},
+			want:      "web.2",
+		},
+		{
+			name:      "plain container",
+			container: "redis-1",
+			labels:    nil,
+			want:      "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serviceName(tt.container, tt.labels); got != tt.want {
+				t.Errorf("serviceName(%q) = %q, want %q", tt.container, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplicaSuffix(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"proj_web_1", "1"},
+		{"proj-web-12", "12"},
+		{"mystack_web.3.q3o5ld6lq8pc", "3"},
+		{"proj-web", ""},
+		{"proj-web-", ""},
+		{"web", ""},
+		{"node.js", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := replicaSuffix(tt.name); got != tt.want {
+			t.Errorf("replicaSuffix(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProjectName(t *testing.T) {
+	tests := []struct {
+		labels map[string]string
+		want   string
+	}{
+		{map[string]string{composeProjectLabel: "shop"}, "shop"},
+		{map[string]string{stackNamespaceLabel: "mystack"}, "mystack"},
+		{map[string]string{composeServiceLabel: "web"}, ""},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		if got := projectName(tt.labels); got != tt.want {
+			t.Errorf("projectName(%v) = %q, want %q", tt.labels, got, tt.want)
+		}
+	}
+}
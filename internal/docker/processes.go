@@ -60,9 +60,9 @@ func (c *Client) GetContainerProcesses(id string) ([]model.Process, error) {
 		processes = append(processes, process)
 	}
 
-	// Limit to top 10
-	if len(processes) > 10 {
-		processes = processes[:10]
+	// Limit to the configured number of processes
+	if c.processLimit > 0 && len(processes) > c.processLimit {
+		processes = processes[:c.processLimit]
 	}
 
 	return processes, nil
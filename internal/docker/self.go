@@ -0,0 +1,89 @@
+// internal/docker/self.go
+package docker
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// SelfIDEnv names an environment variable that can be set to the monitor's own
+// container ID or name when automatic detection does not work
+const SelfIDEnv = "DOCKERMON_SELF_ID"
+
+// shortIDPattern matches the short container ID Docker uses as the default hostname
+var shortIDPattern = regexp.MustCompile(`^[0-9a-f]{12}$`)
+
+// containerIDPattern matches full container IDs in cgroup paths
+// ("/docker/<id>", "docker-<id>.scope") and mount sources ("/containers/<id>/hostname")
+var containerIDPattern = regexp.MustCompile(`(?:docker[-/]|/containers/)([0-9a-f]{64})`)
+
+// selfMountPoints are the files Docker bind-mounts from the container's own
+// directory. Other mounts may come from other containers' directories, e.g.
+// a shared IPC namespace or secrets.
+var selfMountPoints = map[string]bool{
+	"/etc/hostname":    true,
+	"/etc/hosts":       true,
+	"/etc/resolv.conf": true,
+}
+
+// DetectSelfID returns the ID or name of the container the monitor itself runs
+// in, or "" when it does not appear to be containerized
+func DetectSelfID() string {
+	if id := os.Getenv(SelfIDEnv); id != "" {
+		return id
+	}
+
+	// cgroup v1 paths contain the container ID
+	if id := findContainerID("/proc/self/cgroup"); id != "" {
+		return id
+	}
+
+	// The remaining clues can also be seen on the host, so only trust them inside a container
+	if _, err := os.Stat("/.dockerenv"); err != nil {
+		return ""
+	}
+
+	// With cgroup v2 the ID only shows up in the sources of the files Docker bind-mounts
+	if data, err := os.ReadFile("/proc/self/mountinfo"); err == nil {
+		if id := mountedContainerID(string(data)); id != "" {
+			return id
+		}
+	}
+
+	// Docker uses the short container ID as the default hostname, unless it was replaced by a name
+	if hostname, err := os.Hostname(); err == nil && shortIDPattern.MatchString(hostname) {
+		return hostname
+	}
+
+	return ""
+}
+
+// mountedContainerID returns the container ID in the source of the
+// /etc/hostname, /etc/hosts or /etc/resolv.conf mount of a mountinfo file
+func mountedContainerID(mountinfo string) string {
+	for _, line := range strings.Split(mountinfo, "\n") {
+		// Fields: mount ID, parent ID, major:minor, root, mount point, ...
+		fields := strings.Fields(line)
+		if len(fields) < 5 || !selfMountPoints[fields[4]] {
+			continue
+		}
+		if match := containerIDPattern.FindStringSubmatch(fields[3]); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+// findContainerID returns the first container ID found in a file
+func findContainerID(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	if match := containerIDPattern.FindSubmatch(data); match != nil {
+		return string(match[1])
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package docker
+
+import "testing"
+
+const (
+	selfID  = "3f4a5b6c7d8e9f00112233445566778899aabbccddeeff00112233445566aabb"
+	otherID = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+)
+
+var (
+	// Inside a container the hostname, hosts and resolv.conf files are
+	// bind-mounted from the container's own directory
+	containerMountinfo = `500 400 0:50 / / rw,relatime master:200 - overlay overlay rw,lowerdir=/var/lib/docker/overlay2/l/ABC
+501 500 0:52 / /proc rw,nosuid,nodev,noexec,relatime - proc proc rw
+510 500 0:60 /var/lib/docker/containers/` + otherID + `/mounts/shm /dev/shm rw,nosuid,nodev,noexec,relatime - tmpfs shm rw,size=65536k
+511 500 8:1 /var/lib/docker/containers/` + selfID + `/resolv.conf /etc/resolv.conf rw,relatime - ext4 /dev/sda1 rw
+512 500 8:1 /var/lib/docker/containers/` + selfID + `/hostname /etc/hostname rw,relatime - ext4 /dev/sda1 rw
+513 500 8:1 /var/lib/docker/containers/` + selfID + `/hosts /etc/hosts rw,relatime - ext4 /dev/sda1 rw
+`
+
+	// On the host, other containers' directories show up as mounts of their own
+	hostMountinfo = `22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw
+23 22 0:21 / /proc rw,nosuid,nodev,noexec,relatime shared:12 - proc proc rw
+300 22 0:60 / /var/lib/docker/containers/` + otherID + `/mounts/shm rw,nosuid,nodev,noexec,relatime shared:150 - tmpfs shm rw,size=65536k
+301 22 0:61 / /var/lib/docker/containers/` + otherID + `/mounts/secrets rw,relatime shared:151 - tmpfs tmpfs rw
+302 22 0:62 / /run/docker/netns/` + otherID[:12] + ` rw shared:152 - nsfs nsfs rw
+`
+)
+
+func TestMountedContainerID(t *testing.T) {
+	tests := []struct {
+		name      string
+		mountinfo string
+		want      string
+	}{
+		{"container", containerMountinfo, selfID},
+		{"host with other containers' mounts", hostMountinfo, ""},
+		{"empty", "", ""},
+		{"short lines", "1 2 3\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mountedContainerID(tt.mountinfo); got != tt.want {
+				t.Errorf("mountedContainerID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ package docker
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"time"
 
@@ -11,6 +13,9 @@ import (
 	"github.com/rusenback/docker-monitor/internal/model"
 )
 
+// ErrNoStats is returned when Docker has no stats for a container, usually because it is not running
+var ErrNoStats = errors.New("no stats available")
+
 // GetContainerStats retrieves container resource statistics
 func (c *Client) GetContainerStats(id string) (*model.Stats, error) {
 	ctx, cancel := context.WithTimeout(c.Ctx, 5*time.Second)
@@ -23,12 +28,25 @@ func (c *Client) GetContainerStats(id string) (*model.Stats, error) {
 	}
 	defer resp.Body.Close()
 
+	return decodeStats(resp.Body)
+}
+
+// decodeStats reads a single stats sample from a stats response body.
+// It returns ErrNoStats when the body holds no usable sample, and a
+// wrapped decode error when the body is malformed or cut short.
+func decodeStats(r io.Reader) (*model.Stats, error) {
 	var stats types.StatsJSON
-	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+	if err := json.NewDecoder(r).Decode(&stats); err != nil {
 		if err == io.EOF {
-			return nil, nil
+			// Empty body
+			return nil, ErrNoStats
 		}
-		return nil, err
+		return nil, fmt.Errorf("failed to decode stats: %w", err)
+	}
+
+	// Containers that are not running get an all-zero sample without a read time
+	if stats.Read.IsZero() {
+		return nil, ErrNoStats
 	}
 
 	return parseStats(&stats), nil
@@ -38,6 +56,7 @@ func (c *Client) GetContainerStats(id string) (*model.Stats, error) {
 func parseStats(stats *types.StatsJSON) *model.Stats {
 	// Calculate CPU percentage
 	cpuPercent := calculateCPUPercent(stats)
+	onlineCPUs := onlineCPUs(stats)
 
 	// Memory information
 	memUsage := stats.MemoryStats.Usage
@@ -83,6 +102,7 @@ func parseStats(stats *types.StatsJSON) *model.Stats {
 
 	return &model.Stats{
 		CPUPercent:       cpuPercent,
+		OnlineCPUs:       onlineCPUs,
 		MemoryUsage:      memUsage,
 		MemoryLimit:      memLimit,
 		MemoryPercent:    memPercent,
@@ -108,14 +128,21 @@ func calculateCPUPercent(stats *types.StatsJSON) float64 {
 	systemDelta := float64(stats.CPUStats.SystemUsage - stats.PreCPUStats.SystemUsage)
 
 	if systemDelta > 0.0 && cpuDelta > 0.0 {
-		cpuPercent := (cpuDelta / systemDelta) * float64(
-			len(stats.CPUStats.CPUUsage.PercpuUsage),
-		) * 100.0
+		cpuPercent := (cpuDelta / systemDelta) * float64(onlineCPUs(stats)) * 100.0
 		return cpuPercent
 	}
 	return 0.0
 }
 
+// onlineCPUs returns the number of host cores available to the container.
+// cgroup v2 hosts report no per-CPU usage, only online_cpus.
+func onlineCPUs(stats *types.StatsJSON) uint32 {
+	if stats.CPUStats.OnlineCPUs > 0 {
+		return stats.CPUStats.OnlineCPUs
+	}
+	return uint32(len(stats.CPUStats.CPUUsage.PercpuUsage))
+}
+
 // StreamContainerStats streams container statistics
 // Returns a channel for reading stats and an error channel
 func (c *Client) StreamContainerStats(id string) (<-chan *model.Stats, <-chan error, func()) {
@@ -142,10 +169,23 @@ func (c *Client) StreamContainerStats(id string) (<-chan *model.Stats, <-chan er
 		for {
 			var stats types.StatsJSON
 			if err := decoder.Decode(&stats); err != nil {
-				if err == io.EOF || err == context.Canceled {
+				if ctx.Err() != nil {
+					return
+				}
+				if err == io.EOF {
+					// A stream that ends before its first sample had nothing to report
+					if updateCounter == 0 {
+						errChan <- ErrNoStats
+					}
 					return
 				}
-				errChan <- err
+				errChan <- fmt.Errorf("failed to decode stats: %w", err)
+				return
+			}
+
+			// The daemon sends a zeroed sample when the container is not running
+			if stats.Read.IsZero() {
+				errChan <- ErrNoStats
 				return
 			}
 
@@ -228,9 +268,9 @@ func (c *Client) getContainerProcessesWithContext(ctx context.Context, id string
 		processes = append(processes, process)
 	}
 
-	// Limit to top 10
-	if len(processes) > 10 {
-		processes = processes[:10]
+	// Limit to the configured number of processes
+	if c.processLimit > 0 && len(processes) > c.processLimit {
+		processes = processes[:c.processLimit]
 	}
 
 	return processes, nil
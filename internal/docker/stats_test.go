@@ -0,0 +1,118 @@
+package docker
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+const sampleStats = `{
+	"read": "2024-05-01T12:00:00Z",
+	"cpu_stats": {
+		"cpu_usage": {"total_usage": 3000000000},
+		"system_cpu_usage": 20000000000,
+		"online_cpus": 4
+	},
+	"precpu_stats": {
+		"cpu_usage": {"total_usage": 1000000000},
+		"system_cpu_usage": 10000000000
+	},
+	"memory_stats": {"usage": 268435456, "limit": 1073741824},
+	"networks": {
+		"eth0": {"rx_bytes": 1000, "tx_bytes": 500},
+		"eth1": {"rx_bytes": 24, "tx_bytes": 12}
+	},
+	"pids_stats": {"current": 7}
+}`
+
+func TestDecodeStats(t *testing.T) {
+	t.Run("empty body", func(t *testing.T) {
+		_, err := decodeStats(strings.NewReader(""))
+		if !errors.Is(err, ErrNoStats) {
+			t.Errorf("err = %v, want ErrNoStats", err)
+		}
+	})
+
+	t.Run("not running", func(t *testing.T) {
+		// Docker sends an all-zero sample without a read time for stopped containers
+		_, err := decodeStats(strings.NewReader(`{"read": "0001-01-01T00:00:00Z"}`))
+		if !errors.Is(err, ErrNoStats) {
+			t.Errorf("err = %v, want ErrNoStats", err)
+		}
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		_, err := decodeStats(strings.NewReader(sampleStats[:len(sampleStats)/2]))
+		if err == nil || errors.Is(err, ErrNoStats) {
+			t.Fatalf("err = %v, want a decode error", err)
+		}
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("err = %v, want it to wrap io.ErrUnexpectedEOF", err)
+		}
+		if !strings.HasPrefix(err.Error(), "failed to decode stats: ") {
+			t.Errorf("err = %q, want it wrapped with context", err)
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		stats, err := decodeStats(strings.NewReader(sampleStats))
+		if err != nil {
+			t.Fatalf("err = %v", err)
+		}
+
+		// 2s of CPU time over 10s of system time on 4 cores
+		if stats.CPUPercent != 80 {
+			t.Errorf("CPUPercent = %v, want 80", stats.CPUPercent)
+		}
+		if stats.OnlineCPUs != 4 {
+			t.Errorf("OnlineCPUs = %d, want 4", stats.OnlineCPUs)
+		}
+		if stats.MemoryPercent != 25 {
+			t.Errorf("MemoryPercent = %v, want 25", stats.MemoryPercent)
+		}
+		if stats.NetworkRx != 1024 || stats.NetworkTx != 512 {
+			t.Errorf("network = %d/%d, want 1024/512", stats.NetworkRx, stats.NetworkTx)
+		}
+		if stats.PIDs != 7 {
+			t.Errorf("PIDs = %d, want 7", stats.PIDs)
+		}
+		if stats.Timestamp.IsZero() {
+			t.Error("Timestamp is zero, want the read time")
+		}
+	})
+}
+
+func TestCalculateCPUPercentCores(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{
+			// cgroup v2 daemons leave percpu_usage empty and only report online_cpus
+			name: "online_cpus only",
+			body: `{"read": "2024-05-01T12:00:00Z",
+				"cpu_stats": {"cpu_usage": {"total_usage": 3000000000}, "system_cpu_usage": 20000000000, "online_cpus": 4},
+				"precpu_stats": {"cpu_usage": {"total_usage": 1000000000}, "system_cpu_usage": 10000000000}}`,
+		},
+		{
+			// Older daemons report per-CPU usage without online_cpus
+			name: "percpu_usage only",
+			body: `{"read": "2024-05-01T12:00:00Z",
+				"cpu_stats": {"cpu_usage": {"total_usage": 3000000000, "percpu_usage": [1, 1, 1, 1]}, "system_cpu_usage": 20000000000},
+				"precpu_stats": {"cpu_usage": {"total_usage": 1000000000}, "system_cpu_usage": 10000000000}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := decodeStats(strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if stats.CPUPercent != 80 || stats.OnlineCPUs != 4 {
+				t.Errorf("CPU = %v%% of %d cores, want 80%% of 4", stats.CPUPercent, stats.OnlineCPUs)
+			}
+		})
+	}
+}
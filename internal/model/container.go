@@ -7,11 +7,19 @@ type Container struct {
 	ID            string
 	Name          string
 	Image         string
+	ImageID       string // ID of the image, which stays the same when its tag is moved
 	Status        string
 	State         string
 	Created       time.Time
 	Ports         []Port
+	Command       string            // Command the container was created with
+	Labels        map[string]string // Labels from the container list summary
+	SizeRw        int64             // Bytes written by the container, 0 unless sizes were requested
+	SizeRootFs    int64             // Total size of the container filesystem, 0 unless sizes were requested
 	DisplayStatus string
+	IsSelf        bool   // The container dockermon itself is running in
+	ServiceName   string // "service.replica" for Compose and swarm containers, else empty
+	Project       string // Compose project or swarm stack the service belongs to, else empty
 }
 
 // Port edustaa container porttia
@@ -20,3 +28,17 @@ type Port struct {
 	Public  int
 	Type    string
 }
+
+// RunInfo describes the current run of a container as reported by inspect
+type RunInfo struct {
+	State        string
+	Running      bool
+	RestartCount int
+	StartedAt    time.Time
+	FinishedAt   time.Time
+	LogDriver    string
+	LogPath      string
+	CPULimit     float64 // CPU limit in cores, 0 when unlimited
+	OpenStdin    bool    // Started with -i, so input can be attached
+	StdinOnce    bool    // Stdin is closed when the attached client detaches
+}
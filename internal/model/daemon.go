@@ -0,0 +1,15 @@
+// internal/model/daemon.go
+package model
+
+// DaemonInfo describes the Docker daemon and the API version in use
+type DaemonInfo struct {
+	ServerVersion string
+	APIVersion    string // Highest API version the daemon supports
+	MinAPIVersion string
+	ClientAPI     string // API version negotiated by the client
+	OS            string
+	Arch          string
+	KernelVersion string
+	CgroupVersion string // "1" or "2", empty on daemons that do not report it
+	CgroupDriver  string
+}
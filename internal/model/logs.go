@@ -7,5 +7,12 @@ import "time"
 type LogEntry struct {
 	Timestamp time.Time
 	Message   string
-	Stream    string // "stdout" or "stderr"
+	Stream    string // "stdout", "stderr" or "marker" for annotations added by the monitor
+
+	// OutOfOrder is set when the entry arrived too late to be sorted into
+	// place, e.g. after the container's clock jumped backward
+	OutOfOrder bool
 }
+
+// StreamMarker marks entries inserted by the monitor rather than read from the container
+const StreamMarker = "marker"
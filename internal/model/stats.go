@@ -6,7 +6,8 @@ import "time"
 // Stats contains container resource statistics
 type Stats struct {
 	// CPU
-	CPUPercent float64
+	CPUPercent float64 // 100% per fully used core
+	OnlineCPUs uint32  // Host cores available to the container
 
 	// Memory
 	MemoryUsage   uint64
@@ -0,0 +1,131 @@
+// Package scan runs a user-configured vulnerability scanner against container images
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ErrUnavailable is returned when no scanner is configured or it cannot be run
+var ErrUnavailable = errors.New("scan unavailable")
+
+// Result holds vulnerability counts of an image by severity
+type Result struct {
+	Critical  int
+	High      int
+	Medium    int
+	Low       int
+	Unknown   int
+	ScannedAt time.Time
+}
+
+// Total returns the number of vulnerabilities found
+func (r *Result) Total() int {
+	return r.Critical + r.High + r.Medium + r.Low + r.Unknown
+}
+
+// Run executes the scanner command for an image and counts the findings.
+// "{image}" in the command is replaced with the image reference; without it
+// the image is appended as the last argument. The command is split on
+// whitespace and not run through a shell, so quotes are passed on literally;
+// use a wrapper script for arguments that contain spaces.
+func Run(ctx context.Context, command, image string) (*Result, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%w: no scanner configured", ErrUnavailable)
+	}
+
+	substituted := false
+	for i, arg := range args {
+		if strings.Contains(arg, "{image}") {
+			args[i] = strings.ReplaceAll(arg, "{image}", image)
+			substituted = true
+		}
+	}
+	if !substituted {
+		args = append(args, image)
+	}
+
+	output, err := exec.CommandContext(ctx, args[0], args[1:]...).Output()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("%w: %s timed out", ErrUnavailable, args[0])
+	}
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s not found", ErrUnavailable, args[0])
+	}
+
+	// Some scanners exit non-zero when they find vulnerabilities, so only
+	// fail when there is no report to read
+	var exitErr *exec.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && len(output) > 0) {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	result, err := Parse(output)
+	if err != nil && exitErr != nil {
+		// A failing scanner that printed something other than a report,
+		// e.g. an error message on stdout
+		return nil, fmt.Errorf("%w: %s %v: %v", ErrUnavailable, args[0], exitErr, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.ScannedAt = time.Now()
+	return result, nil
+}
+
+// report covers the JSON output of Trivy ("Results") and Grype ("matches")
+type report struct {
+	Results []struct {
+		Vulnerabilities []struct {
+			Severity string `json:"Severity"`
+		} `json:"Vulnerabilities"`
+	} `json:"Results"`
+
+	Matches []struct {
+		Vulnerability struct {
+			Severity string `json:"severity"`
+		} `json:"vulnerability"`
+	} `json:"matches"`
+}
+
+// Parse counts vulnerabilities by severity in a scanner's JSON report
+func Parse(data []byte) (*Result, error) {
+	var r report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse scanner output: %w", err)
+	}
+
+	result := &Result{}
+	for _, target := range r.Results {
+		for _, v := range target.Vulnerabilities {
+			result.add(v.Severity)
+		}
+	}
+	for _, m := range r.Matches {
+		result.add(m.Vulnerability.Severity)
+	}
+
+	return result, nil
+}
+
+// add counts one vulnerability of the given severity
+func (r *Result) add(severity string) {
+	switch strings.ToLower(severity) {
+	case "critical":
+		r.Critical++
+	case "high":
+		r.High++
+	case "medium":
+		r.Medium++
+	case "low", "negligible":
+		r.Low++
+	default:
+		r.Unknown++
+	}
+}
@@ -0,0 +1,86 @@
+package scan
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+const trivyReport = `{"Results": [{"Vulnerabilities": [
+	{"Severity": "CRITICAL"}, {"Severity": "HIGH"}, {"Severity": "HIGH"}, {"Severity": "LOW"}
+]}]}`
+
+// fakeScanner writes a script that prints output and exits with code
+func fakeScanner(t *testing.T, output string, code int) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "scanner")
+	script := "#!/bin/sh\ncat <<'EOF'\n" + output + "\nEOF\nexit " + strconv.Itoa(code) + "\n"
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		output      string
+		code        int
+		wantHigh    int
+		unavailable bool
+	}{
+		{name: "clean exit", output: trivyReport, code: 0, wantHigh: 2},
+		{name: "non-zero exit with a report", output: trivyReport, code: 1, wantHigh: 2},
+		{name: "non-zero exit without a report", output: "FATAL: image not found", code: 1, unavailable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Run(context.Background(), fakeScanner(t, tt.output, tt.code), "nginx:latest")
+			if tt.unavailable {
+				if !errors.Is(err, ErrUnavailable) {
+					t.Errorf("err = %v, want ErrUnavailable", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if result.High != tt.wantHigh || result.Total() != 4 {
+				t.Errorf("result = %+v, want %d high of 4", result, tt.wantHigh)
+			}
+		})
+	}
+}
+
+func TestRunWithoutScanner(t *testing.T) {
+	if _, err := Run(context.Background(), "  ", "nginx"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("err = %v, want ErrUnavailable", err)
+	}
+	if _, err := Run(context.Background(), "dockermon-no-such-scanner", "nginx"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("err = %v, want ErrUnavailable", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	grype := `{"matches": [
+		{"vulnerability": {"severity": "Medium"}},
+		{"vulnerability": {"severity": "Negligible"}},
+		{"vulnerability": {"severity": "Unknown"}}
+	]}`
+
+	result, err := Parse([]byte(grype))
+	if err != nil {
+		t.Fatalf("err = %v", err)
+	}
+	if result.Medium != 1 || result.Low != 1 || result.Unknown != 1 {
+		t.Errorf("result = %+v, want 1 medium, 1 low and 1 unknown", result)
+	}
+
+	if _, err := Parse([]byte("not json")); err == nil || errors.Is(err, ErrUnavailable) {
+		t.Errorf("err = %v, want a parse error", err)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	_ "modernc.org/sqlite"
@@ -63,11 +65,38 @@ type DataPoint struct {
 	MemoryPercent float64
 }
 
+// Write queue limits. Each container gets its own bounded queue so that a
+// container producing many samples only ever drops its own data.
+const (
+	maxPendingPerContainer = 200
+	flushThreshold         = 50
+)
+
+// BreachEvent records a period where a container's usage exceeded its warning threshold
+type BreachEvent struct {
+	ContainerID string
+	Metric      string // "cpu" or "memory"
+	StartedAt   time.Time
+	EndedAt     time.Time
+	Peak        float64
+}
+
+// retention is how long stats and breach events are kept
+const retention = 7 * 24 * time.Hour
+
 // Storage handles persistent statistics storage
 type Storage struct {
-	db        *sql.DB
-	writeChan chan *StatsEntry
-	closeChan chan struct{}
+	db *sql.DB
+
+	mu           sync.Mutex
+	pending      map[string][]*StatsEntry // Queued entries per container
+	pendingCount int
+	dropped      atomic.Uint64
+
+	flushChan   chan struct{}
+	closeChan   chan struct{}
+	writerDone  chan struct{}
+	cleanupDone chan struct{}
 }
 
 // StatsEntry represents a stats entry to be written
@@ -85,15 +114,22 @@ type StatsEntry struct {
 	PIDs          uint64
 }
 
+// dataDir returns the directory holding the stats database
+func dataDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".dockermon"), nil
+}
+
 // NewStorage creates a new storage instance
 func NewStorage() (*Storage, error) {
 	// Create data directory
-	homeDir, err := os.UserHomeDir()
+	dataDir, err := dataDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return nil, err
 	}
-
-	dataDir := filepath.Join(homeDir, ".dockermon")
 	if err := os.MkdirAll(dataDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create data directory: %w", err)
 	}
@@ -112,9 +148,12 @@ func NewStorage() (*Storage, error) {
 	}
 
 	storage := &Storage{
-		db:        db,
-		writeChan: make(chan *StatsEntry, 1000),
-		closeChan: make(chan struct{}),
+		db:          db,
+		pending:     make(map[string][]*StatsEntry),
+		flushChan:   make(chan struct{}, 1),
+		closeChan:   make(chan struct{}),
+		writerDone:  make(chan struct{}),
+		cleanupDone: make(chan struct{}),
 	}
 
 	// Start background writer
@@ -147,6 +186,18 @@ func createTables(db *sql.DB) error {
 	CREATE INDEX IF NOT EXISTS idx_container_time
 	ON container_stats(container_id, timestamp);
 
+	CREATE TABLE IF NOT EXISTS breach_events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		container_id TEXT NOT NULL,
+		metric TEXT NOT NULL,
+		started_at INTEGER NOT NULL,
+		ended_at INTEGER NOT NULL,
+		peak REAL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_breach_container_time
+	ON breach_events(container_id, started_at);
+
 	CREATE TABLE IF NOT EXISTS containers (
 		id TEXT PRIMARY KEY,
 		name TEXT,
@@ -160,51 +211,130 @@ func createTables(db *sql.DB) error {
 	return err
 }
 
-// Write queues a stats entry for writing
+// CheckWritable verifies that the stats database can be created and written
+// without touching it: an existing database is opened for writing and closed,
+// and a scratch database is written next to it and removed. When the data
+// directory does not exist yet, the home directory it would be created in is
+// checked instead. It returns the path of the stats database.
+func CheckWritable() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	dbPath := filepath.Join(dir, "stats.db")
+
+	if f, err := os.OpenFile(dbPath, os.O_WRONLY, 0); err == nil {
+		f.Close()
+	} else if !os.IsNotExist(err) {
+		return dbPath, fmt.Errorf("failed to open database for writing: %w", err)
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		dir = filepath.Dir(dir)
+	}
+	scratch, err := os.CreateTemp(dir, ".dockermon-check-*.db")
+	if err != nil {
+		return dbPath, fmt.Errorf("failed to create a file in %s: %w", dir, err)
+	}
+	scratch.Close()
+	defer os.Remove(scratch.Name())
+
+	db, err := sql.Open("sqlite", scratch.Name())
+	if err != nil {
+		return dbPath, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	if err := createTables(db); err != nil {
+		return dbPath, fmt.Errorf("failed to write to database: %w", err)
+	}
+	return dbPath, nil
+}
+
+// Write queues a stats entry for writing. It never blocks: when a container's
+// queue is full its oldest queued entry is dropped to make room.
 func (s *Storage) Write(entry *StatsEntry) {
-	select {
-	case s.writeChan <- entry:
-		// Successfully queued
-	default:
-		// Channel full, drop silently to avoid blocking
-		// This is acceptable for metrics collection
+	s.mu.Lock()
+	queue := s.pending[entry.ContainerID]
+
+	// Timestamps are stored with second precision, so a second sample for the
+	// same container within the same second would only duplicate the row
+	if n := len(queue); n > 0 && queue[n-1].Timestamp.Unix() == entry.Timestamp.Unix() {
+		queue[n-1] = entry
+		s.mu.Unlock()
+		return
+	}
+
+	if len(queue) >= maxPendingPerContainer {
+		// Channel-style dropping would always hit whoever writes last;
+		// dropping per container keeps the loss with the busiest writer
+		queue = append(queue[:0], queue[1:]...)
+		s.pendingCount--
+		s.dropped.Add(1)
+	}
+
+	s.pending[entry.ContainerID] = append(queue, entry)
+	s.pendingCount++
+	flush := s.pendingCount >= flushThreshold
+	s.mu.Unlock()
+
+	if flush {
+		select {
+		case s.flushChan <- struct{}{}:
+		default:
+			// A flush is already pending
+		}
 	}
 }
 
+// Dropped returns how many queued entries have been discarded because their container's queue was full
+func (s *Storage) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
 // writer runs in background and batch writes to database
 func (s *Storage) writer() {
-	buffer := make([]*StatsEntry, 0, 100)
+	defer close(s.writerDone)
+
 	ticker := time.NewTicker(5 * time.Second) // Flush more frequently
 	defer ticker.Stop()
 
 	for {
 		select {
-		case entry := <-s.writeChan:
-			buffer = append(buffer, entry)
-
-			// Batch write when buffer reaches 50 entries (more frequent writes)
-			if len(buffer) >= 50 {
-				s.batchWrite(buffer)
-				buffer = buffer[:0]
-			}
+		case <-s.flushChan:
+			// Enough entries queued for a batch
+			s.flush()
 
 		case <-ticker.C:
 			// Periodic flush every 5 seconds
-			if len(buffer) > 0 {
-				s.batchWrite(buffer)
-				buffer = buffer[:0]
-			}
+			s.flush()
 
 		case <-s.closeChan:
 			// Final flush on close
-			if len(buffer) > 0 {
-				s.batchWrite(buffer)
-			}
+			s.flush()
 			return
 		}
 	}
 }
 
+// flush takes everything queued and writes it in one batch
+func (s *Storage) flush() {
+	s.mu.Lock()
+	if s.pendingCount == 0 {
+		s.mu.Unlock()
+		return
+	}
+	batch := make([]*StatsEntry, 0, s.pendingCount)
+	for _, queue := range s.pending {
+		batch = append(batch, queue...)
+	}
+	s.pending = make(map[string][]*StatsEntry)
+	s.pendingCount = 0
+	s.mu.Unlock()
+
+	s.batchWrite(batch)
+}
+
 // batchWrite writes a batch of entries to the database
 func (s *Storage) batchWrite(entries []*StatsEntry) {
 	tx, err := s.db.Begin()
@@ -328,8 +458,54 @@ func (s *Storage) scanRows(rows *sql.Rows) ([]DataPoint, error) {
 	return points, rows.Err()
 }
 
+// RecordBreach stores a finished breach event
+func (s *Storage) RecordBreach(event *BreachEvent) error {
+	_, err := s.db.Exec(`
+		INSERT INTO breach_events
+		(container_id, metric, started_at, ended_at, peak)
+		VALUES (?, ?, ?, ?, ?)
+	`,
+		event.ContainerID,
+		event.Metric,
+		event.StartedAt.Unix(),
+		event.EndedAt.Unix(),
+		event.Peak,
+	)
+	return err
+}
+
+// QueryBreaches returns breach events of a container that started after since, newest first
+func (s *Storage) QueryBreaches(containerID string, since time.Time) ([]BreachEvent, error) {
+	rows, err := s.db.Query(`
+		SELECT metric, started_at, ended_at, peak
+		FROM breach_events
+		WHERE container_id = ? AND started_at > ?
+		ORDER BY started_at DESC
+	`, containerID, since.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []BreachEvent
+	for rows.Next() {
+		var startedAt, endedAt int64
+		event := BreachEvent{ContainerID: containerID}
+		if err := rows.Scan(&event.Metric, &startedAt, &endedAt, &event.Peak); err != nil {
+			continue
+		}
+		event.StartedAt = time.Unix(startedAt, 0)
+		event.EndedAt = time.Unix(endedAt, 0)
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
 // cleanup removes old data periodically
 func (s *Storage) cleanup() {
+	defer close(s.cleanupDone)
+
 	ticker := time.NewTicker(1 * time.Hour)
 	defer ticker.Stop()
 
@@ -337,9 +513,12 @@ func (s *Storage) cleanup() {
 		select {
 		case <-ticker.C:
 			// Delete data older than 7 days in batches to avoid locking
-			cutoff := time.Now().Add(-7 * 24 * time.Hour).Unix()
+			cutoff := time.Now().Add(-retention).Unix()
 			s.batchDelete(cutoff)
 
+			// Breach events are few, so a single delete is enough
+			s.db.Exec("DELETE FROM breach_events WHERE ended_at < ?", cutoff)
+
 		case <-s.closeChan:
 			return
 		}
@@ -365,14 +544,19 @@ func (s *Storage) batchDelete(cutoffTimestamp int64) {
 			return
 		}
 
-		// Small sleep to avoid overwhelming the database
-		time.Sleep(100 * time.Millisecond)
+		// Small sleep to avoid overwhelming the database, unless closing
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-s.closeChan:
+			return
+		}
 	}
 }
 
 // Close closes the storage
 func (s *Storage) Close() error {
 	close(s.closeChan)
-	time.Sleep(100 * time.Millisecond) // Allow goroutines to finish
+	<-s.writerDone  // Wait for the final flush
+	<-s.cleanupDone // Wait for a running cleanup to stop
 	return s.db.Close()
 }
@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestStorage(t *testing.T) *Storage {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	s, err := NewStorage()
+	if err != nil {
+		t.Fatalf("NewStorage: %v", err)
+	}
+	return s
+}
+
+func TestConcurrentWrites(t *testing.T) {
+	const (
+		writers = 32
+		samples = 1000
+	)
+
+	s := newTestStorage(t)
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	// One goroutine per container, each sample a second apart so none are merged
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := fmt.Sprintf("container-%d", w)
+			for i := range samples {
+				s.Write(&StatsEntry{
+					ContainerID:   id,
+					Timestamp:     base.Add(time.Duration(i) * time.Second),
+					CPUPercent:    float64(i),
+					MemoryPercent: float64(w),
+					MemoryUsage:   uint64(i),
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	dropped := s.Dropped()
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Reopen to check what actually reached the database
+	s, err := NewStorage()
+	if err != nil {
+		t.Fatalf("reopen storage: %v", err)
+	}
+	defer s.Close()
+
+	var integrity string
+	if err := s.db.QueryRow("PRAGMA integrity_check").Scan(&integrity); err != nil {
+		t.Fatalf("integrity check: %v", err)
+	}
+	if integrity != "ok" {
+		t.Fatalf("integrity check = %q", integrity)
+	}
+
+	var rows uint64
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM container_stats").Scan(&rows); err != nil {
+		t.Fatalf("count rows: %v", err)
+	}
+	if rows+dropped != writers*samples {
+		t.Errorf("%d rows + %d dropped = %d, want %d written", rows, dropped, rows+dropped, writers*samples)
+	}
+
+	// Every row must carry exactly what its writer sent
+	var corrupt int
+	err = s.db.QueryRow(`
+		SELECT COUNT(*) FROM container_stats
+		WHERE timestamp - ? != cpu_percent
+		   OR memory_usage != cpu_percent
+		   OR container_id != 'container-' || CAST(memory_percent AS INTEGER)
+	`, base.Unix()).Scan(&corrupt)
+	if err != nil {
+		t.Fatalf("check rows: %v", err)
+	}
+	if corrupt > 0 {
+		t.Errorf("%d rows do not match the sample that was written", corrupt)
+	}
+
+	// Drops are bounded per container: only samples beyond a full queue are
+	// dropped, oldest first, so the newest ones of every container are stored
+	if dropped > writers*(samples-maxPendingPerContainer) {
+		t.Errorf("dropped %d, want at most %d", dropped, writers*(samples-maxPendingPerContainer))
+	}
+	for w := range writers {
+		id := fmt.Sprintf("container-%d", w)
+		var stored int
+		var newest float64
+		err := s.db.QueryRow("SELECT COUNT(*), MAX(cpu_percent) FROM container_stats WHERE container_id = ?", id).Scan(&stored, &newest)
+		if err != nil {
+			t.Fatalf("samples of %s: %v", id, err)
+		}
+		if samples-stored > samples-maxPendingPerContainer {
+			t.Errorf("dropped %d samples of %s, want at most %d", samples-stored, id, samples-maxPendingPerContainer)
+		}
+		if newest != samples-1 {
+			t.Errorf("newest stored sample of %s = %v, want %d", id, newest, samples-1)
+		}
+	}
+}
+
+func TestBreachRoundTrip(t *testing.T) {
+	s := newTestStorage(t)
+	defer s.Close()
+
+	now := time.Now().Truncate(time.Second)
+	events := []*BreachEvent{
+		{ContainerID: "web", Metric: "cpu", StartedAt: now.Add(-3 * time.Hour), EndedAt: now.Add(-170 * time.Minute), Peak: 91.5},
+		{ContainerID: "web", Metric: "memory", StartedAt: now.Add(-time.Hour), EndedAt: now.Add(-50 * time.Minute), Peak: 97},
+		{ContainerID: "web", Metric: "cpu", StartedAt: now.Add(-48 * time.Hour), EndedAt: now.Add(-47 * time.Hour), Peak: 85},
+		{ContainerID: "db", Metric: "cpu", StartedAt: now.Add(-time.Hour), EndedAt: now, Peak: 99},
+	}
+	for _, event := range events {
+		if err := s.RecordBreach(event); err != nil {
+			t.Fatalf("RecordBreach: %v", err)
+		}
+	}
+
+	// Only web's breaches of the last day, newest first
+	got, err := s.QueryBreaches("web", now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("QueryBreaches: %v", err)
+	}
+	want := []BreachEvent{*events[1], *events[0]}
+	if len(got) != len(want) {
+		t.Fatalf("QueryBreaches = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].ContainerID != want[i].ContainerID || got[i].Metric != want[i].Metric || got[i].Peak != want[i].Peak ||
+			!got[i].StartedAt.Equal(want[i].StartedAt) || !got[i].EndedAt.Equal(want[i].EndedAt) {
+			t.Errorf("breach %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rusenback/docker-monitor/internal/model"
+	"github.com/rusenback/docker-monitor/internal/storage"
+)
+
+// breachHistory is how far back the breach view looks, matching storage retention
+const breachHistory = 7 * 24 * time.Hour
+
+var breachStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FAB387"))
+
+type breachesMsg struct {
+	containerID string
+	events      []storage.BreachEvent
+	err         error
+}
+
+// loadBreaches creates a command that reads the breach history of a container
+func loadBreaches(store *storage.Storage, id string) tea.Cmd {
+	if store == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		events, err := store.QueryBreaches(id, time.Now().Add(-breachHistory))
+		return breachesMsg{containerID: id, events: events, err: err}
+	}
+}
+
+// recordBreaches creates a command that stores finished breaches and reloads the history
+func recordBreaches(store *storage.Storage, id string, events []*storage.BreachEvent) tea.Cmd {
+	if store == nil || len(events) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		for _, event := range events {
+			if err := store.RecordBreach(event); err != nil {
+				return breachesMsg{containerID: id, err: err}
+			}
+		}
+		history, err := store.QueryBreaches(id, time.Now().Add(-breachHistory))
+		return breachesMsg{containerID: id, events: history, err: err}
+	}
+}
+
+// trackBreaches opens, extends and closes breach events from a stats sample
+func (m *Model) trackBreaches(stats *model.Stats) tea.Cmd {
+	thresholds := m.config.AlertThresholds()
+	now := time.Now()
+
+	var finished []*storage.BreachEvent
+	for _, sample := range []struct {
+		metric    string
+		value     float64
+		threshold float64
+	}{
+		{"cpu", m.cpuLimitPercent(stats), thresholds.CPUPercent},
+		{"memory", stats.MemoryPercent, thresholds.MemoryPercent},
+	} {
+		open := m.openBreaches[sample.metric]
+		switch {
+		case sample.value >= sample.threshold && open == nil:
+			m.openBreaches[sample.metric] = &storage.BreachEvent{
+				ContainerID: m.currentContainerID,
+				Metric:      sample.metric,
+				StartedAt:   now,
+				EndedAt:     now,
+				Peak:        sample.value,
+			}
+		case sample.value >= sample.threshold:
+			open.EndedAt = now
+			if sample.value > open.Peak {
+				open.Peak = sample.value
+			}
+		case open != nil:
+			open.EndedAt = now
+			finished = append(finished, open)
+			delete(m.openBreaches, sample.metric)
+		}
+	}
+
+	return recordBreaches(m.storage, m.currentContainerID, finished)
+}
+
+// cpuLimitPercent converts a CPU sample (100% per core) to percent of the
+// container's CPU limit, or of the host cores available to it when unlimited
+func (m Model) cpuLimitPercent(stats *model.Stats) float64 {
+	switch {
+	case m.runInfo != nil && m.runInfo.CPULimit > 0:
+		return stats.CPUPercent / m.runInfo.CPULimit
+	case stats.OnlineCPUs > 0:
+		return stats.CPUPercent / float64(stats.OnlineCPUs)
+	default:
+		return stats.CPUPercent
+	}
+}
+
+// closeBreaches ends all ongoing breaches, e.g. when the stats stream stops
+func (m *Model) closeBreaches() tea.Cmd {
+	if len(m.openBreaches) == 0 {
+		return nil
+	}
+
+	var finished []*storage.BreachEvent
+	containerID := ""
+	for metric, open := range m.openBreaches {
+		containerID = open.ContainerID
+		finished = append(finished, open)
+		delete(m.openBreaches, metric)
+	}
+	return recordBreaches(m.storage, containerID, finished)
+}
+
+// breachSummary returns a one-line count of recent breaches, or "" when there were none
+func (m Model) breachSummary() string {
+	cutoff := time.Now().Add(-24 * time.Hour)
+	counts := map[string]int{}
+	for _, event := range m.breaches {
+		if event.StartedAt.After(cutoff) {
+			counts[event.Metric]++
+		}
+	}
+	for metric := range m.openBreaches {
+		counts[metric]++
+	}
+
+	var parts []string
+	if n := counts["cpu"]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d CPU", n))
+	}
+	if n := counts["memory"]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d memory", n))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return breachStyle.Render(fmt.Sprintf("⚠ %s breaches in last 24h [b] details", strings.Join(parts, ", ")))
+}
+
+// renderBreaches renders the breach history of the selected container
+func (m Model) renderBreaches(container *model.Container, width, height int) string {
+	var s strings.Builder
+	thresholds := m.config.AlertThresholds()
+
+	s.WriteString(fmt.Sprintf("Limit breaches: %s (last 7 days)\n", container.Name))
+	s.WriteString(helpStyle.UnsetPadding().Render(fmt.Sprintf(
+		"Thresholds: CPU %.0f%%, memory %.0f%% | [b] back", thresholds.CPUPercent, thresholds.MemoryPercent)) + "\n\n")
+
+	var events []storage.BreachEvent
+	for _, open := range m.openBreaches {
+		events = append(events, *open)
+	}
+	events = append(events, m.breaches...)
+
+	if len(events) == 0 {
+		s.WriteString("No breaches recorded")
+		return s.String()
+	}
+
+	header := fmt.Sprintf("%-16s %-8s %-7s %8s %s", "STARTED", "DURATION", "METRIC", "PEAK", "")
+	s.WriteString(headerStyle.Render(truncate(header, width)) + "\n")
+
+	maxRows := height - 6
+	for i, event := range events {
+		if i >= maxRows {
+			s.WriteString(fmt.Sprintf("... %d more", len(events)-i))
+			break
+		}
+
+		ongoing := ""
+		if i < len(m.openBreaches) {
+			ongoing = "ongoing"
+		}
+		duration := event.EndedAt.Sub(event.StartedAt).Round(time.Second)
+		row := fmt.Sprintf("%-16s %-8s %-7s %7.1f%% %s",
+			event.StartedAt.Format("01-02 15:04:05"),
+			duration,
+			event.Metric,
+			event.Peak,
+			ongoing)
+		s.WriteString(breachStyle.Render(row) + "\n")
+	}
+
+	return s.String()
+}
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/rusenback/docker-monitor/internal/config"
+	"github.com/rusenback/docker-monitor/internal/model"
+	"github.com/rusenback/docker-monitor/internal/storage"
+)
+
+func TestTrackBreaches(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store, err := storage.NewStorage()
+	if err != nil {
+		t.Fatalf("NewStorage: %v", err)
+	}
+	defer store.Close()
+
+	m := NewModel(&fakeClient{}, store, &config.Config{Self: config.SelfOff})
+	m = update(t, m, containersMsg{containers: []model.Container{{ID: "aaa", Name: "web", State: "running"}}})
+
+	// Memory crosses the default 80% threshold, peaks and drops back
+	for _, memory := range []float64{50, 85, 95, 90} {
+		if cmd := m.trackBreaches(&model.Stats{MemoryPercent: memory, OnlineCPUs: 1}); cmd != nil {
+			t.Fatalf("breach at %v%% memory was finished while still over the threshold", memory)
+		}
+	}
+	open := m.openBreaches["memory"]
+	if open == nil || open.Peak != 95 || open.ContainerID != "aaa" || len(m.openBreaches) != 1 {
+		t.Fatalf("open breaches = %+v, want one memory breach of aaa peaking at 95", m.openBreaches)
+	}
+
+	cmd := m.trackBreaches(&model.Stats{MemoryPercent: 40, OnlineCPUs: 1})
+	if len(m.openBreaches) != 0 {
+		t.Errorf("open breaches = %+v, want the memory breach closed", m.openBreaches)
+	}
+	if cmd == nil {
+		t.Fatal("closed breach was not recorded")
+	}
+	msg := cmd().(breachesMsg)
+	if msg.err != nil {
+		t.Fatalf("record breach: %v", msg.err)
+	}
+	if len(msg.events) != 1 || msg.events[0].Metric != "memory" || msg.events[0].Peak != 95 {
+		t.Errorf("breach history = %+v, want the memory breach peaking at 95", msg.events)
+	}
+}
+
+func TestCPULimitPercent(t *testing.T) {
+	tests := []struct {
+		name     string
+		cpuLimit float64 // Cores, 0 when unlimited
+		cpu      float64
+		cores    uint32
+		want     float64
+		breach   bool // Over the default threshold of 80% of the limit
+	}{
+		{"limited to half a core", 0.5, 45, 8, 90, true},
+		{"limited to two cores", 2, 180, 8, 90, true},
+		{"unlimited", 0, 200, 4, 50, false},
+		{"unlimited without core count", 0, 85, 0, 85, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(&fakeClient{})
+			m = update(t, m, containersMsg{containers: []model.Container{{ID: "aaa", Name: "web", State: "running"}}})
+			m.runInfo = &model.RunInfo{CPULimit: tt.cpuLimit}
+
+			stats := &model.Stats{CPUPercent: tt.cpu, OnlineCPUs: tt.cores}
+			if got := m.cpuLimitPercent(stats); got != tt.want {
+				t.Errorf("cpuLimitPercent() = %v, want %v", got, tt.want)
+			}
+
+			m.trackBreaches(stats)
+			if breach := m.openBreaches["cpu"] != nil; breach != tt.breach {
+				t.Errorf("CPU breach opened = %v at %v%% of the limit, want %v", breach, tt.want, tt.breach)
+			}
+		})
+	}
+}
@@ -36,28 +36,37 @@ func fetchContainers(client docker.DockerClient) tea.Cmd {
 }
 
 // waitForStats creates a command that waits for the next stats message
-func waitForStats(statsChan <-chan *model.Stats, errChan <-chan error) tea.Cmd {
+func waitForStats(containerID string, statsChan <-chan *model.Stats, errChan <-chan error) tea.Cmd {
 	return func() tea.Msg {
 		select {
-		case stats := <-statsChan:
-			return statsMsg{stats: stats, err: nil}
-		case err := <-errChan:
-			return statsMsg{stats: nil, err: err}
+		case stats, ok := <-statsChan:
+			if !ok {
+				return statsMsg{containerID: containerID, ended: true}
+			}
+			return statsMsg{containerID: containerID, stats: stats, err: nil}
+		case err, ok := <-errChan:
+			if !ok {
+				return statsMsg{containerID: containerID, ended: true}
+			}
+			return statsMsg{containerID: containerID, stats: nil, err: err}
 		}
 	}
 }
 
 // waitForLogs creates a command that waits for the next log entry
-func waitForLogs(logsChan <-chan model.LogEntry, errChan <-chan error) tea.Cmd {
+func waitForLogs(containerID string, logsChan <-chan model.LogEntry, errChan <-chan error) tea.Cmd {
 	return func() tea.Msg {
 		select {
 		case entry, ok := <-logsChan:
 			if !ok {
-				return nil
+				return logsMsg{containerID: containerID, stream: logsChan, ended: true}
+			}
+			return logsMsg{containerID: containerID, stream: logsChan, entry: entry, err: nil}
+		case err, ok := <-errChan:
+			if !ok {
+				return logsMsg{containerID: containerID, stream: logsChan, ended: true}
 			}
-			return logsMsg{entry: entry, err: nil}
-		case err := <-errChan:
-			return logsMsg{err: err}
+			return logsMsg{containerID: containerID, stream: logsChan, err: err}
 		}
 	}
 }
@@ -80,8 +89,11 @@ func waitForLogsStream(logsChan <-chan model.LogEntry, errChan <-chan error) tea
 func startContainer(client docker.DockerClient, id, name string) tea.Cmd {
 	return func() tea.Msg {
 		return actionMsg{
-			message: fmt.Sprintf("Started: %s", name),
-			err:     client.StartContainer(id),
+			action:      "start",
+			containerID: id,
+			name:        name,
+			message:     fmt.Sprintf("Started: %s", name),
+			err:         client.StartContainer(id),
 		}
 	}
 }
@@ -90,8 +102,11 @@ func startContainer(client docker.DockerClient, id, name string) tea.Cmd {
 func stopContainer(client docker.DockerClient, id, name string) tea.Cmd {
 	return func() tea.Msg {
 		return actionMsg{
-			message: fmt.Sprintf("Stopped: %s", name),
-			err:     client.StopContainer(id),
+			action:      "stop",
+			containerID: id,
+			name:        name,
+			message:     fmt.Sprintf("Stopped: %s", name),
+			err:         client.StopContainer(id),
 		}
 	}
 }
@@ -100,8 +115,106 @@ func stopContainer(client docker.DockerClient, id, name string) tea.Cmd {
 func restartContainer(client docker.DockerClient, id, name string) tea.Cmd {
 	return func() tea.Msg {
 		return actionMsg{
-			message: fmt.Sprintf("Restarted: %s", name),
-			err:     client.RestartContainer(id),
+			action:      "restart",
+			containerID: id,
+			name:        name,
+			message:     fmt.Sprintf("Restarted: %s", name),
+			err:         client.RestartContainer(id),
 		}
 	}
 }
+
+// restartAndFollow creates a command that restarts a container so the logs of its new run can be followed
+func restartAndFollow(client docker.DockerClient, id, name string) tea.Cmd {
+	restart := restartContainer(client, id, name)
+	return func() tea.Msg {
+		msg := restart().(actionMsg)
+		msg.action = "follow"
+		return msg
+	}
+}
+
+// waitForRun creates a command that polls a restarted container until a run
+// started after previousStart has begun, giving up after followTimeout
+func waitForRun(client docker.DockerClient, id, name string, previousStart time.Time) tea.Cmd {
+	return func() tea.Msg {
+		deadline := time.Now().Add(followTimeout)
+		for {
+			info, err := client.GetContainerRunInfo(id)
+			if err != nil {
+				return followReadyMsg{containerID: id, name: name, err: err}
+			}
+			// A run that already ended again still has logs worth showing
+			if info.StartedAt.After(previousStart) {
+				return followReadyMsg{containerID: id, name: name, info: info}
+			}
+			if time.Now().After(deadline) {
+				return followReadyMsg{containerID: id, name: name,
+					err: fmt.Errorf("container is %s", info.State)}
+			}
+			time.Sleep(500 * time.Millisecond)
+		}
+	}
+}
+
+// fetchRunInfo creates a command to inspect the current run of a container
+func fetchRunInfo(client docker.DockerClient, id string) tea.Cmd {
+	return func() tea.Msg {
+		info, err := client.GetContainerRunInfo(id)
+		return runInfoMsg{containerID: id, info: info, err: err}
+	}
+}
+
+// resumeLogs creates a command that inspects a container before its log stream is reopened
+func resumeLogs(client docker.DockerClient, id string) tea.Cmd {
+	return func() tea.Msg {
+		info, err := client.GetContainerRunInfo(id)
+		return logsResumeMsg{containerID: id, info: info, err: err}
+	}
+}
+
+// loadEarlierLogs creates a command that reads rotated log files and the full
+// log history Docker still holds for a container
+func loadEarlierLogs(client docker.DockerClient, id string) tea.Cmd {
+	return func() tea.Msg {
+		archived, archiveErr := client.GetArchivedLogs(id)
+		history, err := client.GetContainerLogs(id, maxLogEntries)
+		return earlierLogsMsg{
+			containerID: id,
+			archived:    archived,
+			history:     history,
+			archiveErr:  archiveErr,
+			err:         err,
+		}
+	}
+}
+
+// attachStdin creates a command to attach to a container's stdin
+func attachStdin(client docker.DockerClient, id, name string) tea.Cmd {
+	return func() tea.Msg {
+		stdin, err := client.AttachStdin(id)
+		return stdinAttachedMsg{containerID: id, name: name, stdin: stdin, err: err}
+	}
+}
+
+// killContainer creates a command to force-kill a container
+func killContainer(client docker.DockerClient, id, name string) tea.Cmd {
+	return func() tea.Msg {
+		return actionMsg{
+			action:      "kill",
+			containerID: id,
+			name:        name,
+			message:     fmt.Sprintf("Killed: %s", name),
+			err:         client.KillContainer(id),
+		}
+	}
+}
+
+// watchdogCheck creates a command that re-inspects a container a little while
+// after its stop timed out, since Docker often finishes stopping it anyway
+func watchdogCheck(client docker.DockerClient, id, name string) tea.Cmd {
+	return tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+		info, err := client.GetContainerRunInfo(id)
+		return watchdogMsg{containerID: id, name: name, info: info, err: err}
+	})
+}
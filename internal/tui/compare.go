@@ -0,0 +1,191 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rusenback/docker-monitor/internal/docker"
+	"github.com/rusenback/docker-monitor/internal/model"
+)
+
+// snapshotMsg carries one stats sample per container, keyed by container ID
+type snapshotMsg struct {
+	stats map[string]*model.Stats
+}
+
+// snapshotWorkers bounds how many stats requests a snapshot runs at once.
+// Each one-shot request holds the daemon for about a second while it
+// measures CPU usage, so the treemap over many containers must not start
+// them all together.
+const snapshotWorkers = 8
+
+// fetchStatsSnapshot creates a command that samples the stats of several containers
+func fetchStatsSnapshot(client docker.DockerClient, ids []string) tea.Cmd {
+	return func() tea.Msg {
+		var (
+			mu sync.Mutex
+			wg sync.WaitGroup
+		)
+		stats := make(map[string]*model.Stats, len(ids))
+		sem := make(chan struct{}, snapshotWorkers)
+
+		for _, id := range ids {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				s, err := client.GetContainerStats(id)
+				if err != nil {
+					// Containers that stopped in the meantime are simply left out
+					return
+				}
+				mu.Lock()
+				stats[id] = s
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		return snapshotMsg{stats: stats}
+	}
+}
+
+// pinnedContainers returns the pinned containers that currently exist, in pin
+// order, and the number of pins whose container is gone
+func (m Model) pinnedContainers() ([]model.Container, int) {
+	byName := make(map[string]model.Container, len(m.allContainers))
+	for _, c := range m.allContainers {
+		byName[c.Name] = c
+	}
+
+	var pinned []model.Container
+	missing := 0
+	for _, name := range m.config.Pins {
+		if c, ok := byName[name]; ok {
+			pinned = append(pinned, c)
+		} else {
+			missing++
+		}
+	}
+	return pinned, missing
+}
+
+// restorePins reopens the comparison view when a pinned container exists.
+// Pins of missing containers are kept, as a stack may just be down at
+// startup, and the view skips them until they reappear.
+func (m *Model) restorePins() {
+	pinned, _ := m.pinnedContainers()
+	m.showCompare = len(pinned) > 0
+}
+
+// refreshSnapshot samples the stats of the running containers the open views
+// need (pinned ones for comparison, all for the treemap) unless a sample is in flight
+func (m *Model) refreshSnapshot() tea.Cmd {
+	if m.snapshotting {
+		return nil
+	}
+
+	containers := m.allContainers
+	if !m.showTreemap {
+		if !m.showCompare {
+			return nil
+		}
+		containers, _ = m.pinnedContainers()
+	}
+
+	var ids []string
+	for _, c := range containers {
+		if c.State == "running" {
+			ids = append(ids, c.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	m.snapshotting = true
+	return fetchStatsSnapshot(m.client, ids)
+}
+
+// handleCompareKey handles keys while the comparison view is open, reporting whether the key was used
+func (m *Model) handleCompareKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "V", "esc":
+		m.showCompare = false
+		return nil, true
+
+	case "D":
+		m.config.ClearPins()
+		if err := m.config.Save(); err != nil {
+			m.message = fmt.Sprintf("Error: %v", err)
+		}
+		m.showCompare = false
+		return nil, true
+	}
+
+	return nil, msg.String() != "q" && msg.String() != "ctrl+c"
+}
+
+// renderCompareView renders the pinned containers side by side
+func (m Model) renderCompareView() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("📌 Pinned containers") + "\n\n")
+
+	pinned, missing := m.pinnedContainers()
+	if len(pinned) == 0 {
+		s.WriteString("No pinned containers. Press * in the container list to pin one.\n")
+	}
+
+	nameWidth := 24
+	header := fmt.Sprintf("%-*s %-8s %-30s %-24s %-21s %5s",
+		nameWidth, "NAME", "STATE", "CPU", "MEMORY", "NET RX/TX", "PIDS")
+	if len(pinned) > 0 {
+		s.WriteString(headerStyle.Render(header) + "\n")
+	}
+
+	for _, c := range pinned {
+		name := truncate(m.displayName(c), nameWidth)
+		stats := m.snapshots[c.ID]
+
+		if c.State != "running" || stats == nil {
+			state := stoppedStyle.Render(fmt.Sprintf("%-8s", c.State))
+			if c.State == "running" {
+				state = runningStyle.Render(fmt.Sprintf("%-8s", c.State))
+			}
+			s.WriteString(fmt.Sprintf("%-*s %s %s\n", nameWidth, name, state, helpStyle.UnsetPadding().Render("-")))
+			continue
+		}
+
+		cpu := fmt.Sprintf("%6.1f%% %s", stats.CPUPercent, compareBar(stats.CPUPercent/float64(max(stats.OnlineCPUs, 1)), 20))
+		mem := fmt.Sprintf("%-10s %5.1f%%", formatBytes(stats.MemoryUsage), stats.MemoryPercent)
+		net := fmt.Sprintf("%s / %s", formatBytes(stats.NetworkRx), formatBytes(stats.NetworkTx))
+
+		s.WriteString(fmt.Sprintf("%-*s %s %-30s %-24s %-21s %5d\n",
+			nameWidth, name,
+			runningStyle.Render(fmt.Sprintf("%-8s", c.State)),
+			cpu, mem, truncate(net, 21), stats.PIDs))
+	}
+
+	if missing > 0 {
+		s.WriteString(helpStyle.UnsetPadding().Render(
+			fmt.Sprintf("\n%d pinned container(s) not found", missing)) + "\n")
+	}
+
+	s.WriteString(helpStyle.Render("[*] pin in list  [D] clear all pins  [V/esc] close"))
+
+	return focusedPanelStyle.
+		Width(m.width - 4).
+		Height(m.height - 4).
+		Render(s.String())
+}
+
+// compareBar renders a usage bar for a percentage of the host
+func compareBar(percent float64, length int) string {
+	filled := int(percent / 100 * float64(length))
+	filled = min(max(filled, 0), length)
+	return strings.Repeat("█", filled) + strings.Repeat("─", length-filled)
+}
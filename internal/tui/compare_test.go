@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rusenback/docker-monitor/internal/docker"
+	"github.com/rusenback/docker-monitor/internal/model"
+)
+
+// slowStatsClient answers stats requests after a delay and records how many ran at once
+type slowStatsClient struct {
+	docker.DockerClient
+	active, peak atomic.Int32
+}
+
+func (c *slowStatsClient) GetContainerStats(id string) (*model.Stats, error) {
+	n := c.active.Add(1)
+	defer c.active.Add(-1)
+	for {
+		peak := c.peak.Load()
+		if n <= peak || c.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if id == "gone" {
+		return nil, docker.ErrNoStats
+	}
+	return &model.Stats{CPUPercent: 1}, nil
+}
+
+func TestFetchStatsSnapshotBoundsConcurrency(t *testing.T) {
+	ids := []string{"gone"}
+	for i := range 4 * snapshotWorkers {
+		ids = append(ids, fmt.Sprintf("c%d", i))
+	}
+
+	client := &slowStatsClient{}
+	msg := fetchStatsSnapshot(client, ids)().(snapshotMsg)
+
+	if peak := client.peak.Load(); peak > snapshotWorkers {
+		t.Errorf("%d stats requests ran at once, want at most %d", peak, snapshotWorkers)
+	}
+	if len(msg.stats) != len(ids)-1 {
+		t.Errorf("snapshot has %d containers, want %d", len(msg.stats), len(ids)-1)
+	}
+	if _, ok := msg.stats["gone"]; ok {
+		t.Error("snapshot includes a container whose stats failed")
+	}
+}
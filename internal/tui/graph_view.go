@@ -6,16 +6,48 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/rusenback/docker-monitor/internal/config"
 	"github.com/rusenback/docker-monitor/internal/storage"
 )
 
 var (
 	graphTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#B4BEFE"))
 	graphAxisStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#6C7086"))
-	cpuGraphStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#89B4FA"))
-	memGraphStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#A6E3A1"))
 )
 
+// graphOptions controls how the combined graph presents its data
+type graphOptions struct {
+	// coreBasis plots CPU in cores on an axis from 0 to this many cores; 0 plots percent
+	coreBasis float64
+
+	theme graphTheme
+}
+
+// colors returns the theme to draw with, falling back to the default theme
+func (o graphOptions) colors() graphTheme {
+	if o.theme.cpuChar == "" {
+		return graphThemes[config.ThemeDefault]
+	}
+	return o.theme
+}
+
+// axisLabel returns the 5-character Y-axis label for a fraction of the axis height
+func (o graphOptions) axisLabel(fraction float64) string {
+	if o.coreBasis > 0 {
+		return fmt.Sprintf("%4.1fc", fraction*o.coreBasis)
+	}
+	return fmt.Sprintf("%3.0f%% ", fraction*100)
+}
+
+// memoryAxisLabel returns the right-hand Y-axis label for memory, which only
+// needs its own axis when the left one is in cores
+func (o graphOptions) memoryAxisLabel(fraction float64) string {
+	if o.coreBasis > 0 {
+		return fmt.Sprintf(" %3.0f%%", fraction*100)
+	}
+	return ""
+}
+
 // renderGraph creates an ASCII line graph
 func renderGraph(data []float64, height int, label string, color lipgloss.Style) string {
 	if len(data) == 0 {
@@ -148,6 +180,7 @@ func renderDualGraphWithRange(
 	cpuData, memData []float64,
 	width, height int,
 	timeRange storage.TimeRange,
+	opts graphOptions,
 ) string {
 	var s strings.Builder
 
@@ -172,14 +205,14 @@ func renderDualGraphWithRange(
 	}
 
 	// Render combined multi-line graph
-	combinedGraph := renderCombinedGraph(cpuData, memData, width-8, graphHeight)
+	combinedGraph := renderCombinedGraph(cpuData, memData, width-8, graphHeight, opts)
 	s.WriteString(combinedGraph)
 
 	return s.String()
 }
 
 // renderCombinedGraph creates a multi-line ASCII graph with both CPU and Memory
-func renderCombinedGraph(cpuData, memData []float64, width, height int) string {
+func renderCombinedGraph(cpuData, memData []float64, width, height int, opts graphOptions) string {
 	var s strings.Builder
 
 	// Ensure we have data
@@ -187,25 +220,30 @@ func renderCombinedGraph(cpuData, memData []float64, width, height int) string {
 		return "Waiting for data..."
 	}
 
+	theme := opts.colors()
+
 	// Get current values
 	cpuCurrent := cpuData[len(cpuData)-1]
 	memCurrent := memData[len(memData)-1]
 
 	// Legend with overlap color
-	cpuLegend := cpuGraphStyle.Render(
-		"█",
-	) + " CPU: " + cpuGraphStyle.Render(
-		fmt.Sprintf("%.1f%%", cpuCurrent),
-	)
-	memLegend := memGraphStyle.Render(
-		"█",
-	) + " Memory: " + memGraphStyle.Render(
-		fmt.Sprintf("%.1f%%", memCurrent),
-	)
-	overlapLegend := lipgloss.NewStyle().Foreground(lipgloss.Color("#CBA6F7")).Render("█") + " Both"
+	cpuValue := fmt.Sprintf("%.1f%%", cpuCurrent)
+	memValue := fmt.Sprintf("%.1f%%", memCurrent)
+	if opts.coreBasis > 0 {
+		// CPU uses the left axis in cores, memory the right axis in percent
+		cpuValue = fmt.Sprintf("%.2f cores", cpuCurrent/100)
+		memValue += " of limit (right axis)"
+	}
+	cpuLegend := theme.cpu.Render(
+		theme.cpuChar,
+	) + " CPU: " + theme.cpu.Render(cpuValue)
+	memLegend := theme.memory.Render(
+		theme.memoryChar,
+	) + " Memory: " + theme.memory.Render(memValue)
+	overlapLegend := theme.overlap.Render(theme.overlapChar) + " Both"
 	s.WriteString(cpuLegend + "  " + memLegend + "  " + overlapLegend + "\n\n")
 
-	// Find global min/max for consistent scaling
+	// Both series share a 0-100 scale; in cores mode CPU is scaled to its share of the core basis
 	minVal, maxVal := 0.0, 100.0
 
 	// Limit data points to available width (leave room for Y-axis labels)
@@ -225,6 +263,14 @@ func renderCombinedGraph(cpuData, memData []float64, width, height int) string {
 	}
 	displayCPU := cpuData[startIdx:]
 	displayMem := memData[startIdx:]
+	if opts.coreBasis > 0 {
+		scaled := make([]float64, len(displayCPU))
+		for i, v := range displayCPU {
+			// percent / 100 = cores; cores / basis * 100 = share of the axis
+			scaled[i] = v / opts.coreBasis
+		}
+		displayCPU = scaled
+	}
 
 	// Render the vertical graph (top to bottom)
 	for row := height; row >= 0; row-- {
@@ -237,16 +283,21 @@ func renderCombinedGraph(cpuData, memData []float64, width, height int) string {
 		}
 
 		// Y-axis label (every few rows)
-		if row == height {
-			line.WriteString(graphAxisStyle.Render(fmt.Sprintf("%3.0f%% ", maxVal)))
-		} else if row == height*3/4 {
-			line.WriteString(graphAxisStyle.Render(" 75% "))
-		} else if row == height/2 {
-			line.WriteString(graphAxisStyle.Render(" 50% "))
-		} else if row == height/4 {
-			line.WriteString(graphAxisStyle.Render(" 25% "))
-		} else if row == 0 {
-			line.WriteString(graphAxisStyle.Render(fmt.Sprintf("%3.0f%% ", minVal)))
+		fraction := -1.0
+		switch row {
+		case height:
+			fraction = 1
+		case height * 3 / 4:
+			fraction = 0.75
+		case height / 2:
+			fraction = 0.5
+		case height / 4:
+			fraction = 0.25
+		case 0:
+			fraction = 0
+		}
+		if fraction >= 0 {
+			line.WriteString(graphAxisStyle.Render(opts.axisLabel(fraction)))
 		} else {
 			line.WriteString("     ")
 		}
@@ -271,19 +322,23 @@ func renderCombinedGraph(cpuData, memData []float64, width, height int) string {
 				line.WriteString(graphAxisStyle.Render("·"))
 			} else if cpuAbove && memAbove {
 				// Both are above threshold - show overlay character
-				line.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#CBA6F7")).Render("█"))
+				line.WriteString(theme.overlap.Render(theme.overlapChar))
 			} else if cpuAbove {
 				// Only CPU above
-				line.WriteString(cpuGraphStyle.Render("█"))
+				line.WriteString(theme.cpu.Render(theme.cpuChar))
 			} else if memAbove {
 				// Only Memory above
-				line.WriteString(memGraphStyle.Render("█"))
+				line.WriteString(theme.memory.Render(theme.memoryChar))
 			} else {
 				// Neither above
 				line.WriteString(" ")
 			}
 		}
 
+		if fraction >= 0 {
+			line.WriteString(graphAxisStyle.Render(opts.memoryAxisLabel(fraction)))
+		}
+
 		s.WriteString(line.String() + "\n")
 	}
 
@@ -1,13 +1,131 @@
 package tui
 
-// truncate shortens a string to a maximum length
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/rusenback/docker-monitor/internal/model"
+)
+
+// truncate shortens a string to a maximum length, leaving out the ellipsis
+// when there is no room for it
 func truncate(s string, max int) string {
-	if len(s) <= max {
+	switch {
+	case len(s) <= max:
 		return s
+	case max <= 0:
+		return ""
+	case max <= 3:
+		return s[:max]
 	}
 	return s[:max-3] + "..."
 }
 
+// displayName returns the name a container is shown with: its
+// "service.replica" name when preferred and known, otherwise its real name.
+// With several projects running the project is included, e.g. "shop/web.1".
+func (m Model) displayName(c model.Container) string {
+	if !m.config.ServiceNames || c.ServiceName == "" {
+		return c.Name
+	}
+	if m.multipleProjects && c.Project != "" {
+		return c.Project + "/" + c.ServiceName
+	}
+	return c.ServiceName
+}
+
+// hasMultipleProjects reports whether containers from more than one Compose
+// project or swarm stack are present
+func hasMultipleProjects(containers []model.Container) bool {
+	project := ""
+	for _, c := range containers {
+		if c.Project == "" || c.Project == project {
+			continue
+		}
+		if project != "" {
+			return true
+		}
+		project = c.Project
+	}
+	return false
+}
+
+// formatBytes renders a byte count with a decimal unit
+func formatBytes(b uint64) string {
+	switch {
+	case b > 1_000_000_000:
+		return fmt.Sprintf("%.2f GB", float64(b)/1_000_000_000)
+	case b > 1_000_000:
+		return fmt.Sprintf("%.2f MB", float64(b)/1_000_000)
+	case b > 1_000:
+		return fmt.Sprintf("%.2f KB", float64(b)/1_000)
+	default:
+		return fmt.Sprintf("%d B", b)
+	}
+}
+
+// shortImageName reduces an image reference to "name:tag" by dropping the
+// registry host (including any port), the repository path and the digest
+func shortImageName(ref string) string {
+	// Containers whose image was removed report the bare image ID
+	if id, ok := strings.CutPrefix(ref, "sha256:"); ok {
+		if len(id) > 12 {
+			id = id[:12]
+		}
+		return id
+	}
+
+	short := ref
+	if i := strings.Index(short, "@"); i >= 0 {
+		short = short[:i]
+	}
+
+	// Everything up to the last slash is the registry host and namespace,
+	// e.g. "registry.example.com:5000/team/" or "docker.io/library/"
+	if i := strings.LastIndex(short, "/"); i >= 0 {
+		short = short[i+1:]
+	}
+
+	if short == "" {
+		return ref
+	}
+	return short
+}
+
+// coreBasis returns the number of cores CPU usage is measured against:
+// the container's CPU limit, or else the host cores available to it
+func (m Model) coreBasis() float64 {
+	if m.runInfo != nil && m.runInfo.CPULimit > 0 {
+		return m.runInfo.CPULimit
+	}
+	if m.currentStats != nil && m.currentStats.OnlineCPUs > 0 {
+		return float64(m.currentStats.OnlineCPUs)
+	}
+	return float64(runtime.NumCPU())
+}
+
+// statsCoreBasis returns the core basis for the stats panel, 0 when showing percent
+func (m Model) statsCoreBasis() float64 {
+	if !m.cpuCores {
+		return 0
+	}
+	return m.coreBasis()
+}
+
+// graphOptions returns the graph settings matching the stats panel
+func (m Model) graphOptions() graphOptions {
+	return graphOptions{
+		coreBasis: m.statsCoreBasis(),
+		theme:     m.graphTheme,
+	}
+}
+
+// inStdinMode reports whether typed input goes to the container's stdin
+func (m Model) inStdinMode() bool {
+	return m.prompt != nil && m.prompt.kind == promptStdin
+}
+
 // calculateVisibleLogLines calculates how many log lines can fit in the panel
 func (m Model) calculateVisibleLogLines() int {
 	// Bottom panel is 40% of height
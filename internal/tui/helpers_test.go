@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rusenback/docker-monitor/internal/model"
+)
+
+func TestShortImageName(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"nginx", "nginx"},
+		{"nginx:1.25", "nginx:1.25"},
+		{"docker.io/library/nginx:latest", "nginx:latest"},
+		{"library/redis:7", "redis:7"},
+		{"ghcr.io/org/app:v2", "app:v2"},
+		{"host:5000/x/y:tag", "y:tag"},
+		{"localhost:5000/app", "app"},
+		{"postgres@sha256:4f1c0a0e2b7d", "postgres"},
+		{"docker.io/library/postgres:16@sha256:4f1c0a0e2b7d", "postgres:16"},
+		{"host:5000/x/y@sha256:4f1c0a0e2b7d", "y"},
+		{"sha256:0123456789abcdef0123456789abcdef", "0123456789ab"},
+		{"sha256:abc", "abc"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := shortImageName(tt.ref); got != tt.want {
+			t.Errorf("shortImageName(%q) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s    string
+		max  int
+		want string
+	}{
+		{"container", -1, ""},
+		{"container", 0, ""},
+		{"container", 1, "c"},
+		{"container", 3, "con"},
+		{"container", 4, "c..."},
+		{"container", 8, "conta..."},
+		{"container", 9, "container"},
+		{"container", 20, "container"},
+		{"", 0, ""},
+	}
+
+	for _, tt := range tests {
+		if got := truncate(tt.s, tt.max); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestRenderListPanelContentTinySizes(t *testing.T) {
+	m := newTestModel(nil)
+	m.loading = false
+	m.containers = []model.Container{
+		{ID: "a", Name: "a-container-with-a-rather-long-name", Image: "docker.io/library/postgres:16", State: "running", DisplayStatus: "Up 2 hours"},
+		{ID: "b", Name: "b", Image: "redis", State: "exited", DisplayStatus: "Exited (0) 5 minutes ago"},
+	}
+
+	// The list reserves 10 rows for the title, summary, header and help
+	const reserved = 10
+	for _, width := range []int{0, 1, 2, 9, 10, 11, 80} {
+		for _, height := range []int{0, 1, reserved - 1, reserved, reserved + 1, 40} {
+			t.Run(fmt.Sprintf("%dx%d", width, height), func(t *testing.T) {
+				content := m.renderListPanelContent(width, height)
+
+				rows := min(max(height-reserved, 0), len(m.containers))
+				hint := strings.Contains(content, "↕ resize for list")
+				if hint != (rows == 0) {
+					t.Errorf("resize hint shown = %v, want %v", hint, rows == 0)
+				}
+				// The summary says "running" once, then each listed running container
+				if got := strings.Count(content, "running") - 1; got != min(rows, 1) {
+					t.Errorf("listed %d running containers, want %d", got, min(rows, 1))
+				}
+			})
+		}
+	}
+}
+
+func TestDisplayName(t *testing.T) {
+	shopWeb := model.Container{Name: "shop-web-1", ServiceName: "web.1", Project: "shop"}
+	blogWeb := model.Container{Name: "blog-web-1", ServiceName: "web.1", Project: "blog"}
+	shopDB := model.Container{Name: "shop-db-1", ServiceName: "db.1", Project: "shop"}
+	plain := model.Container{Name: "redis"}
+
+	tests := []struct {
+		name         string
+		serviceNames bool
+		containers   []model.Container
+		want         []string
+	}{
+		{
+			name:       "service names off",
+			containers: []model.Container{shopWeb, blogWeb, plain},
+			want:       []string{"shop-web-1", "blog-web-1", "redis"},
+		},
+		{
+			name:         "one project",
+			serviceNames: true,
+			containers:   []model.Container{shopWeb, shopDB, plain},
+			want:         []string{"web.1", "db.1", "redis"},
+		},
+		{
+			name:         "several projects",
+			serviceNames: true,
+			containers:   []model.Container{shopWeb, blogWeb, shopDB, plain},
+			want:         []string{"shop/web.1", "blog/web.1", "shop/db.1", "redis"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(nil)
+			m.config.ServiceNames = tt.serviceNames
+			m = update(t, m, containersMsg{containers: tt.containers})
+
+			for i, c := range tt.containers {
+				if got := m.displayName(c); got != tt.want[i] {
+					t.Errorf("displayName(%s) = %q, want %q", c.Name, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLabelPairs(t *testing.T) {
+	labels := map[string]string{"b": "2", "a": "1", "c": "3", "d": "4"}
+
+	tests := []struct {
+		limit int
+		want  string
+	}{
+		{10, "a=1 b=2 c=3 d=4"},
+		{3, "a=1 b=2 c=3 d=4"}, // A "+1 more" line would take the same room
+		{2, "a=1 b=2 +2 more"},
+	}
+
+	for _, tt := range tests {
+		if got := strings.Join(labelPairs(labels, tt.limit), " "); got != tt.want {
+			t.Errorf("labelPairs(limit %d) = %q, want %q", tt.limit, got, tt.want)
+		}
+	}
+	if got := labelPairs(nil, 3); len(got) != 0 {
+		t.Errorf("labelPairs(nil) = %q, want none", got)
+	}
+}
@@ -0,0 +1,209 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rusenback/docker-monitor/internal/model"
+	"github.com/rusenback/docker-monitor/internal/scan"
+)
+
+const (
+	// scanTimeout bounds how long an external scanner may run
+	scanTimeout = 5 * time.Minute
+
+	// inspectLabelRows is how many labels are listed before the rest are counted
+	inspectLabelRows = 8
+)
+
+var (
+	inspectLabelStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#6C7086")).Width(14)
+
+	criticalBadgeStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#1E1E2E")).Background(lipgloss.Color("#F38BA8")).Padding(0, 1)
+	highBadgeStyle     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#1E1E2E")).Background(lipgloss.Color("#FAB387")).Padding(0, 1)
+	mediumBadgeStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#1E1E2E")).Background(lipgloss.Color("#F9E2AF")).Padding(0, 1)
+)
+
+// imageScan is the cached scan state of an image
+type imageScan struct {
+	running bool
+	result  *scan.Result
+	err     error
+}
+
+type scanMsg struct {
+	key    string // Cache key of the scanned image, see scanKey
+	result *scan.Result
+	err    error
+}
+
+// scanKey returns the key a container's image scan is cached under: the
+// image ID, so a tag that moved to a new image is not shown the old result
+func scanKey(c model.Container) string {
+	if c.ImageID != "" {
+		return c.ImageID
+	}
+	return c.Image
+}
+
+// runScan creates a command that runs the configured scanner for an image
+func runScan(command, key, image string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), scanTimeout)
+		defer cancel()
+
+		result, err := scan.Run(ctx, command, image)
+		return scanMsg{key: key, result: result, err: err}
+	}
+}
+
+// handleInspectKey handles keys while the inspect overlay is open, reporting whether the key was used
+func (m *Model) handleInspectKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "enter", "esc":
+		m.showInspect = false
+		return nil, true
+
+	case "v":
+		if len(m.containers) == 0 {
+			return nil, true
+		}
+		container := m.containers[m.cursor]
+		key := scanKey(container)
+		if state := m.scans[key]; state != nil && state.running {
+			return nil, true
+		}
+		m.scans[key] = &imageScan{running: true}
+		return runScan(m.config.Scanner, key, container.Image), true
+	}
+
+	// Everything else is swallowed so the overlay's container does not change under it
+	return nil, msg.String() != "q" && msg.String() != "ctrl+c"
+}
+
+// renderInspectOverlay renders container details and the image scan result centered on screen
+func (m Model) renderInspectOverlay() string {
+	if len(m.containers) == 0 {
+		return ""
+	}
+	container := m.containers[m.cursor]
+
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("🔍 "+container.Name) + "\n\n")
+
+	row := func(label, value string) {
+		s.WriteString(inspectLabelStyle.Render(label) + value + "\n")
+	}
+
+	valueWidth := max(m.width-30, 10)
+	row("ID", container.ID)
+	row("Image", container.Image)
+	if container.Command != "" {
+		row("Command", truncate(container.Command, valueWidth))
+	}
+	row("State", fmt.Sprintf("%s (%s)", container.State, container.Status))
+	row("Created", container.Created.Format("2006-01-02 15:04:05"))
+	row("Ports", formatPorts(container.Ports))
+	if container.SizeRootFs > 0 {
+		row("Size", fmt.Sprintf("%s written, %s total", formatBytes(uint64(container.SizeRw)), formatBytes(uint64(container.SizeRootFs))))
+	}
+	label := "Labels"
+	for _, pair := range labelPairs(container.Labels, inspectLabelRows) {
+		row(label, truncate(pair, valueWidth))
+		label = ""
+	}
+
+	// Run details are only loaded for the container being followed
+	if m.runInfo != nil && container.ID == m.currentContainerID {
+		if !m.runInfo.StartedAt.IsZero() {
+			row("Started", m.runInfo.StartedAt.Local().Format("2006-01-02 15:04:05"))
+		}
+		row("Restarts", fmt.Sprintf("%d", m.runInfo.RestartCount))
+		row("Log driver", m.runInfo.LogDriver)
+		if m.runInfo.CPULimit > 0 {
+			row("CPU limit", fmt.Sprintf("%.2f cores", m.runInfo.CPULimit))
+		}
+		row("Stdin", map[bool]string{true: "open", false: "closed"}[m.runInfo.OpenStdin])
+	}
+
+	s.WriteString("\n")
+	row("Vulnerabilities", m.renderScanBadge(scanKey(container)))
+
+	s.WriteString(helpStyle.Render("[v] scan image  [enter/esc] close"))
+
+	box := focusedPanelStyle.Render(s.String())
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// renderScanBadge renders the CVE counts for an image, or the scan state
+func (m Model) renderScanBadge(key string) string {
+	state := m.scans[key]
+	switch {
+	case state == nil:
+		if m.config.Scanner == "" {
+			return helpStyle.UnsetPadding().Render(`scan unavailable (set "scanner" in config)`)
+		}
+		return helpStyle.UnsetPadding().Render("not scanned")
+	case state.running:
+		return helpStyle.UnsetPadding().Render("scanning...")
+	case state.err != nil:
+		return stoppedStyle.Render(state.err.Error())
+	}
+
+	r := state.result
+	if r.Total() == 0 {
+		return runningStyle.Render("none found") + helpStyle.UnsetPadding().Render(
+			" (scanned "+r.ScannedAt.Format("15:04")+")")
+	}
+
+	badges := []string{
+		criticalBadgeStyle.Render(fmt.Sprintf("C %d", r.Critical)),
+		highBadgeStyle.Render(fmt.Sprintf("H %d", r.High)),
+		mediumBadgeStyle.Render(fmt.Sprintf("M %d", r.Medium)),
+	}
+	other := r.Low + r.Unknown
+	return strings.Join(badges, " ") + helpStyle.UnsetPadding().Render(
+		fmt.Sprintf(" +%d low/unknown (scanned %s)", other, r.ScannedAt.Format("15:04")))
+}
+
+// formatPorts renders port mappings like "8080->80/tcp"
+func formatPorts(ports []model.Port) string {
+	if len(ports) == 0 {
+		return "-"
+	}
+
+	parts := make([]string, 0, len(ports))
+	for _, p := range ports {
+		if p.Public > 0 {
+			parts = append(parts, fmt.Sprintf("%d->%d/%s", p.Public, p.Private, p.Type))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d/%s", p.Private, p.Type))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// labelPairs renders labels as sorted "key=value" pairs, listing at most
+// limit of them and counting the rest in a last line
+func labelPairs(labels map[string]string, limit int) []string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, min(len(keys), limit+1))
+	for i, key := range keys {
+		if i == limit && len(keys) > limit+1 {
+			pairs = append(pairs, fmt.Sprintf("+%d more", len(keys)-limit))
+			break
+		}
+		pairs = append(pairs, key+"="+labels[key])
+	}
+	return pairs
+}
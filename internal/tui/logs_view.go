@@ -23,12 +23,16 @@ var (
 	// Styles for log levels
 	timestampStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#6C7086")) // Dim gray
 
+	outOfOrderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FAB387")) // Orange
+
 	errorLogStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#F38BA8"))   // Red
 	warningLogStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FAB387"))   // Orange
 	infoLogStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#89B4FA"))   // Blue
 	debugLogStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#6C7086"))   // Dim
 	defaultLogStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#CDD6F4"))   // Normal
 
+	markerLogStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F9E2AF")).Bold(true) // Yellow
+
 	// Stream indicators
 	stdoutIndicator = lipgloss.NewStyle().Foreground(lipgloss.Color("#A6E3A1")).Render("○") // Green circle
 	stderrIndicator = lipgloss.NewStyle().Foreground(lipgloss.Color("#F38BA8")).Render("●") // Red circle
@@ -43,6 +47,15 @@ var (
 func styleLogEntry(entry model.LogEntry, maxWidth int) string {
 	// Format timestamp (dimmed)
 	timestamp := timestampStyle.Render(entry.Timestamp.Format("15:04:05"))
+	if entry.OutOfOrder {
+		// Flag entries whose time went backward so they are not read as the latest
+		timestamp = outOfOrderStyle.Render(entry.Timestamp.Format("15:04:05") + "↶")
+	}
+
+	// Annotations added by the monitor get their own style and no highlighting
+	if entry.Stream == model.StreamMarker {
+		return timestamp + " " + markerLogStyle.Render(truncate(entry.Message, maxWidth-lipgloss.Width(timestamp)-1))
+	}
 
 	// Stream indicator
 	streamIndicator := stdoutIndicator
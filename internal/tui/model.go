@@ -1,9 +1,11 @@
 package tui
 
 import (
+	"io"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rusenback/docker-monitor/internal/config"
 	"github.com/rusenback/docker-monitor/internal/docker"
 	"github.com/rusenback/docker-monitor/internal/model"
 	"github.com/rusenback/docker-monitor/internal/storage"
@@ -12,15 +14,21 @@ import (
 // Model represents the TUI application state
 type Model struct {
 	client        docker.DockerClient
-	containers    []model.Container
+	config           *config.Config
+	allContainers    []model.Container // Everything Docker reported, before filtering
+	containers       []model.Container // Containers shown in the list
 	cursor        int
 	err           error
 	loading       bool
 	message       string
+	messageUntil     time.Time // When a flash message may be replaced by the stats refresh
 	currentStats     *model.Stats
 	previousStats    *model.Stats // For calculating rates
 	currentProcesses []model.Process
+	processCursor    int    // Selected row in the processes table
+	selectedPID      string // PID of the selected process, followed across refreshes
 	statsCancel      func()
+	statsNote        string // Why no stats are shown, e.g. the container is not running
 	width         int
 	height        int
 
@@ -29,8 +37,36 @@ type Model struct {
 	logsScroll     int
 	logsAutoScroll bool
 
-	logsChan    <-chan model.LogEntry
-	logsErrChan <-chan error
+	logsChan     <-chan model.LogEntry
+	logsErrChan  <-chan error
+	logsResuming bool // A resume check is in flight after the log stream ended
+
+	runInfo *model.RunInfo // Inspect details for the current container's run
+
+	// Container left running after a timed-out stop, offered for force-kill
+	stuck *stuckContainer
+
+	// Inverse of the last start/stop, nil once used or expired
+	undo *undoAction
+
+	// Container being restarted to follow the logs of its new run
+	following string
+
+	// Selected container that just left running, kept on screen in case it comes right back
+	flap *flapState
+
+	// Inspect overlay and the vulnerability scans cached per image ID
+	showInspect bool
+	scans       map[string]*imageScan
+
+	// Comparison view of pinned containers and the usage treemap, both
+	// drawn from stats snapshots sampled while they are shown
+	showCompare   bool
+	showTreemap   bool
+	treemapMemory bool
+	snapshots     map[string]*model.Stats
+	snapshotting  bool
+	pinsRestored  bool // The comparison view was restored from the saved pins
 
 	statsChan    <-chan *model.Stats
 	statsErrChan <-chan error
@@ -48,8 +84,60 @@ type Model struct {
 
 	// Panel focus for highlighting
 	focusedPanel PanelType
+
+	// Show full image references instead of the short "name:tag" form
+	showFullImage bool
+
+	// Show CPU usage in cores instead of percent
+	cpuCores bool
+
+	// Colors and characters of the combined graph
+	graphTheme graphTheme
+
+	// Only list containers carrying this tag, empty shows all
+	tagFilter string
+
+	// ID or name of the container the monitor runs in, empty when not containerized
+	selfID string
+
+	// Containers of several Compose projects are present, so service names include the project
+	multipleProjects bool
+
+	// Active text prompt, nil when not collecting input
+	prompt *promptState
+
+	// Attached stdin of the current container while in input mode
+	stdin *stdinWriter
+
+	// Playback of stored history in the graph panel, nil when not replaying
+	replay *replayState
+
+	// Threshold breaches of the current container: ongoing ones by metric,
+	// and the stored history shown in the stats panel
+	openBreaches map[string]*storage.BreachEvent
+	breaches     []storage.BreachEvent
+	showBreaches bool
 }
 
+// Transient message and undo timing
+const (
+	messageTimeout = 5 * time.Second
+	undoWindow     = 10 * time.Second
+)
+
+// flapGrace is how long the panels of a container that stopped running are
+// kept before they are cleared
+const flapGrace = 3 * time.Second
+
+// followTimeout bounds how long restart-and-follow waits for the container to run again
+const followTimeout = 30 * time.Second
+
+// maxLogEntries caps the log buffer to prevent memory issues
+const maxLogEntries = 1000
+
+// logReorderWindow is how many of the newest log entries a late entry may be sorted among
+const logReorderWindow = 50
+
 // PanelType represents the different panels in the UI
 type PanelType int
 
@@ -69,29 +157,123 @@ type containersMsg struct {
 }
 
 type actionMsg struct {
-	message string
-	err     error
+	action      string // "start", "stop", "restart", "follow" or "kill"
+	containerID string
+	name        string
+	message     string
+	err         error
+}
+
+// undoAction is the inverse of the last reversible action, offered for a short window
+type undoAction struct {
+	action  string // "start" or "stop"
+	id      string
+	name    string
+	expires time.Time
+}
+
+// flapState is a container that stopped running less than flapGrace ago
+type flapState struct {
+	id    string
+	until time.Time
+}
+
+type flapGraceMsg struct {
+	containerID string
+	until       time.Time
+}
+
+type clearMessageMsg struct {
+	text string
+}
+
+// stuckContainer is a container whose stop timed out and that was still running afterwards
+type stuckContainer struct {
+	id   string
+	name string
+}
+
+type watchdogMsg struct {
+	containerID string
+	name        string
+	info        *model.RunInfo
+	err         error
 }
 
 type statsMsg struct {
-	stats *model.Stats
-	err   error
+	containerID string
+	stats       *model.Stats
+	err         error
+	ended       bool // The stream closed, e.g. because the container stopped
 }
 
 type logsMsg struct {
-	entry model.LogEntry
-	err   error
+	containerID string
+	stream      <-chan model.LogEntry // Identifies the stream the message came from
+	entry       model.LogEntry
+	err         error
+	ended       bool // The stream closed, e.g. because the container stopped
+}
+
+type stdinAttachedMsg struct {
+	containerID string
+	name        string
+	stdin       io.WriteCloser
+	err         error
+}
+
+// stdinDoneMsg reports that a stdin writer stopped, with the write error if any
+type stdinDoneMsg struct {
+	containerID string
+	writer      *stdinWriter
+	err         error
+}
+
+type runInfoMsg struct {
+	containerID string
+	info        *model.RunInfo
+	err         error
+}
+
+type followReadyMsg struct {
+	containerID string
+	name        string
+	info        *model.RunInfo
+	err         error
+}
+
+type logsResumeMsg struct {
+	containerID string
+	info        *model.RunInfo
+	err         error
+}
+
+type earlierLogsMsg struct {
+	containerID string
+	archived    []model.LogEntry
+	history     []model.LogEntry
+	archiveErr  error
+	err         error
 }
 
 // NewModel creates a new TUI model
-func NewModel(client docker.DockerClient, store *storage.Storage) Model {
+func NewModel(client docker.DockerClient, store *storage.Storage, cfg *config.Config) Model {
 	maxPoints := 150
 	// Pre-fill with zeros so graph is full-width from the start
 	cpuHist := make([]float64, maxPoints)
 	memHist := make([]float64, maxPoints)
 
+	selfID := ""
+	if cfg.SelfMode() != config.SelfOff {
+		selfID = docker.DetectSelfID()
+	}
+
 	return Model{
 		client:        client,
+		selfID:        selfID,
+		cpuCores:      cfg.CPUUnit == config.CPUCores,
+		graphTheme:    newGraphTheme(cfg.Theme, cfg.GraphColors),
+		config:        cfg,
 		loading:       true,
 		maxDataPoints: maxPoints,
 		cpuHistory:    cpuHist,
@@ -99,6 +281,9 @@ func NewModel(client docker.DockerClient, store *storage.Storage) Model {
 		storage:       store,
 		timeRange:     storage.Range30Min, // Default to 30 minutes
 		focusedPanel:  PanelContainerList,  // Start with container list focused
+		openBreaches:  make(map[string]*storage.BreachEvent),
+		scans:         make(map[string]*imageScan),
+		snapshots:     make(map[string]*model.Stats),
 	}
 }
 
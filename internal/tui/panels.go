@@ -2,8 +2,9 @@ package tui
 
 import (
 	"fmt"
-	"github.com/charmbracelet/lipgloss"
 	"strings"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 // renderContainerListPanel renders the container list panel
@@ -40,14 +41,18 @@ func (m Model) renderListPanelContent(width, height int) string {
 			running++
 		}
 	}
-	s.WriteString(fmt.Sprintf("%d total, %d running\n\n", len(m.containers), running))
+	s.WriteString(fmt.Sprintf("%d total, %d running", len(m.containers), running))
+	if m.tagFilter != "" {
+		s.WriteString(fmt.Sprintf(" | tag: #%s [T] next", m.tagFilter))
+	}
+	s.WriteString("\n\n")
 
-	// Adjusted column widths for the panel
-	colWidth := width - 10
+	// Adjusted column widths for the panel, never negative on tiny terminals
+	colWidth := max(width-10, 0)
 	nameWidth := int(float64(colWidth) * 0.25)
 	imageWidth := int(float64(colWidth) * 0.30)
 	stateWidth := 10
-	statusWidth := colWidth - nameWidth - imageWidth - stateWidth
+	statusWidth := max(colWidth-nameWidth-imageWidth-stateWidth, 0)
 
 	header := fmt.Sprintf("%-*s %-*s %-*s %-*s",
 		nameWidth, "NAME",
@@ -58,17 +63,36 @@ func (m Model) renderListPanelContent(width, height int) string {
 
 	// Calculate how many containers we can show
 	maxContainers := height - 10 // Reserve space for header, help, etc.
+	if maxContainers <= 0 {
+		s.WriteString(helpStyle.UnsetPadding().Render("↕ resize for list") + "\n")
+	}
 
 	for i, container := range m.containers {
 		if i >= maxContainers {
 			break
 		}
 
-		name := truncate(container.Name, nameWidth)
-		image := truncate(container.Image, imageWidth)
+		name := m.displayName(container)
+		if m.config.IsPinned(container.Name) {
+			name = "* " + name
+		}
+		if container.IsSelf {
+			name += " (this monitor)"
+		}
+		if tags := m.config.TagsFor(container.Name); len(tags) > 0 {
+			name += " #" + strings.Join(tags, " #")
+		}
+		name = truncate(name, nameWidth)
+		image := container.Image
+		if !m.showFullImage {
+			image = shortImageName(image)
+		}
+		image = truncate(image, imageWidth)
 
 		var stateStr string
-		if container.State == "running" {
+		if m.stuck != nil && m.stuck.id == container.ID {
+			stateStr = stoppedStyle.Render("stuck")
+		} else if container.State == "running" {
 			stateStr = runningStyle.Render("running")
 		} else {
 			stateStr = stoppedStyle.Render(container.State)
@@ -92,7 +116,9 @@ func (m Model) renderListPanelContent(width, height int) string {
 		s.WriteString("\n")
 	}
 
-	if m.message != "" {
+	if m.prompt != nil && m.prompt.kind != promptStdin {
+		s.WriteString("\n" + m.prompt.render() + "\n")
+	} else if m.message != "" {
 		s.WriteString("\n" + m.message + "\n")
 	}
 
@@ -107,7 +133,12 @@ func (m Model) renderGraphPanel(width, height int) string {
 	var content string
 
 	// Query data from storage if available
-	if m.storage != nil && m.currentContainerID != "" {
+	if m.replay != nil {
+		content = renderReplayGraph(m.replay, width-4, height-4, m.graphOptions())
+	} else if m.showTreemap {
+		items, idle := m.treemapItems()
+		content = renderTreemap(items, idle, m.treemapMemory, width-4, height-4)
+	} else if m.storage != nil && m.currentContainerID != "" {
 		dataPoints, err := m.storage.Query(m.currentContainerID, m.timeRange)
 		if err == nil && len(dataPoints) > 0 {
 			// Convert to separate CPU and Memory slices
@@ -117,14 +148,14 @@ func (m Model) renderGraphPanel(width, height int) string {
 				cpuData[i] = dp.CPUPercent
 				memData[i] = dp.MemoryPercent
 			}
-			content = renderDualGraphWithRange(cpuData, memData, width-4, height-4, m.timeRange)
+			content = renderDualGraphWithRange(cpuData, memData, width-4, height-4, m.timeRange, m.graphOptions())
 		} else {
 			// Fallback to in-memory data
-			content = renderDualGraphWithRange(m.cpuHistory, m.memoryHistory, width-4, height-4, m.timeRange)
+			content = renderDualGraphWithRange(m.cpuHistory, m.memoryHistory, width-4, height-4, m.timeRange, m.graphOptions())
 		}
 	} else {
 		// Use in-memory data
-		content = renderDualGraphWithRange(m.cpuHistory, m.memoryHistory, width-4, height-4, m.timeRange)
+		content = renderDualGraphWithRange(m.cpuHistory, m.memoryHistory, width-4, height-4, m.timeRange, m.graphOptions())
 	}
 
 	style := panelStyle
@@ -146,17 +177,31 @@ func (m Model) renderLogPanel(width, height int) string {
 		s.WriteString("No container selected")
 	} else {
 		container := m.containers[m.cursor]
-		s.WriteString(fmt.Sprintf("Container: %s", container.Name))
+		s.WriteString(fmt.Sprintf("Container: %s", m.displayName(container)))
+
+		// Docker only follows the current run, so make earlier restarts visible
+		if m.runInfo != nil && m.runInfo.RestartCount > 0 {
+			s.WriteString(fmt.Sprintf(" (%d restarts, L: earlier logs)", m.runInfo.RestartCount))
+		}
 
 		// Show auto-scroll indicator
 		autoScrollIndicator := ""
 		if m.logsAutoScroll {
 			autoScrollIndicator = " [Auto-scroll: ON]"
 		}
-		s.WriteString(autoScrollIndicator + "\n\n")
+		s.WriteString(autoScrollIndicator)
+		if m.inStdinMode() {
+			s.WriteString(" " + selectedStyle.Render(" INPUT ") + " [esc] leave")
+		}
+		s.WriteString("\n\n")
 
-		if len(m.logs) == 0 {
+		if len(m.logs) == 0 && m.following != "" && m.following == m.currentContainerID {
+			s.WriteString("Waiting for container...")
+		} else if len(m.logs) == 0 {
 			s.WriteString("No logs yet...")
+			if m.inStdinMode() {
+				s.WriteString("\n\n" + m.prompt.render())
+			}
 		} else {
 			// Calculate visible lines: cut 2 more lines (12 total reserved)
 			// Height includes borders (4) + title (2) + container name (2) + help (2) + spacing (2) = 12
@@ -206,8 +251,10 @@ func (m Model) renderLogPanel(width, height int) string {
 				s.WriteString(strings.Join(logLines, "\n"))
 			}
 
-			// Show scroll indicator if there are more logs
-			if totalLogs > visibleLines {
+			// The input line replaces the scroll indicator while in input mode
+			if m.inStdinMode() {
+				s.WriteString("\n\n" + m.prompt.render())
+			} else if totalLogs > visibleLines {
 				s.WriteString(fmt.Sprintf("\n\n[%d-%d/%d] PgUp/PgDown | a:auto | c:clear",
 					start+1, end, totalLogs))
 			}
@@ -249,21 +296,43 @@ func (m Model) renderStatsPanelContent(width, height int) string {
 
 	container := m.containers[m.cursor]
 
-	if container.State != "running" {
-		s.WriteString(fmt.Sprintf("Container: %s\n\n", container.Name))
+	if container.State != "running" && !m.inFlapGrace(container.ID) {
+		s.WriteString(fmt.Sprintf("Container: %s\n\n", m.displayName(container)))
 		s.WriteString("Container must be running\nto view stats")
 		return s.String()
 	}
+	if m.inFlapGrace(container.ID) {
+		s.WriteString(stoppedStyle.Render(fmt.Sprintf("%s stopped, waiting for it to come back...", m.displayName(container))) + "\n")
+	}
 
-	// Use current stats with stored processes
-	statsWithProcesses := m.currentStats
-	if statsWithProcesses != nil && len(m.currentProcesses) > 0 {
-		// Create a copy with processes
-		statsCopy := *statsWithProcesses
-		statsCopy.Processes = m.currentProcesses
-		s.WriteString(RenderStats(&container, &statsCopy))
-	} else {
-		s.WriteString(RenderStats(&container, m.currentStats))
+	if m.showBreaches {
+		s.WriteString(m.renderBreaches(&container, width-8, height-8))
+		return s.String()
+	}
+
+	if summary := m.breachSummary(); summary != "" {
+		s.WriteString(summary + "\n")
+	}
+	if m.currentStats == nil && m.statsNote != "" {
+		s.WriteString(fmt.Sprintf("Container: %s\n\n", m.displayName(container)))
+		s.WriteString(helpStyle.UnsetPadding().Render(m.statsNote))
+		return s.String()
+	}
+	s.WriteString(RenderStats(&container, m.currentStats, m.statsCoreBasis()))
+
+	// Fit the processes table into whatever height the stats leave over:
+	// borders and padding (6), table title and header (3), spacing and detail line (2)
+	if m.currentStats != nil {
+		rows := height - 6 - lipgloss.Height(s.String()) - 5
+		if rows < 1 {
+			rows = 1
+		}
+		s.WriteString(renderProcesses(m.currentProcesses, processTable{
+			cursor:  m.processCursor,
+			rows:    rows,
+			width:   width - 8,
+			focused: m.focusedPanel == PanelStats,
+		}))
 	}
 
 	return s.String()
@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rusenback/docker-monitor/internal/config"
+	"github.com/rusenback/docker-monitor/internal/model"
+)
+
+// promptKind identifies what a text prompt is collecting input for
+type promptKind int
+
+const (
+	promptTags promptKind = iota
+	promptStdin
+)
+
+// promptState holds a single-line text prompt shown at the bottom of the container list
+type promptState struct {
+	kind   promptKind
+	label  string
+	value  string
+	target string // Container name the input applies to
+}
+
+// handlePromptKey edits the active prompt; every key goes to the prompt while it is open
+func (m Model) handlePromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		if m.prompt.kind == promptStdin {
+			m.closeStdin()
+		}
+		m.prompt = nil
+		m.message = ""
+
+	case tea.KeyEnter:
+		p := m.prompt
+		m.prompt = nil
+		return m.submitPrompt(p)
+
+	case tea.KeyBackspace:
+		if runes := []rune(m.prompt.value); len(runes) > 0 {
+			m.prompt.value = string(runes[:len(runes)-1])
+		}
+
+	case tea.KeySpace:
+		m.prompt.value += " "
+
+	case tea.KeyRunes:
+		m.prompt.value += string(msg.Runes)
+	}
+
+	return m, nil
+}
+
+// submitPrompt applies the input of a completed prompt
+func (m Model) submitPrompt(p *promptState) (tea.Model, tea.Cmd) {
+	switch p.kind {
+	case promptTags:
+		tags := config.ParseTags(p.value)
+		m.config.SetTags(p.target, tags)
+		if err := m.config.Save(); err != nil {
+			m.message = fmt.Sprintf("Error: %v", err)
+		} else if len(tags) == 0 {
+			m.message = fmt.Sprintf("Removed tags from %s", p.target)
+		} else {
+			m.message = fmt.Sprintf("Tagged %s: %s", p.target, strings.Join(tags, ", "))
+		}
+
+		// The container may no longer match the active tag filter
+		m.applyFilters()
+		return m, m.updateStatsAndLogsForCursor()
+
+	case promptStdin:
+		// Stay in input mode for the next line, like a terminal would
+		m.prompt = &promptState{kind: promptStdin, label: p.label, target: p.target}
+		if m.stdin == nil {
+			return m, nil
+		}
+		if !m.stdin.send(p.value) {
+			m.message = "Input not sent: the container is not reading its stdin"
+			return m, nil
+		}
+		m.appendLog(model.LogEntry{
+			Timestamp: time.Now(),
+			Message:   "> " + p.value,
+			Stream:    model.StreamMarker,
+		})
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// closeStdin detaches from the container's stdin, if attached
+func (m *Model) closeStdin() {
+	if m.stdin != nil {
+		m.stdin.close()
+		m.stdin = nil
+	}
+}
+
+// render renders the prompt line with a cursor
+func (p *promptState) render() string {
+	return p.label + p.value + "█"
+}
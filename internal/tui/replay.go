@@ -0,0 +1,199 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rusenback/docker-monitor/internal/storage"
+)
+
+// replayFrameInterval is how often playback advances
+const replayFrameInterval = 150 * time.Millisecond
+
+// replayTargetFrames is roughly how many frames a full playback takes,
+// so long ranges advance several points per frame
+const replayTargetFrames = 200
+
+var replayTimestampStyle = lipgloss.NewStyle().
+	Bold(true).
+	Foreground(lipgloss.Color("#1E1E2E")).
+	Background(lipgloss.Color("#F9E2AF")).
+	Padding(0, 1)
+
+// replayState steps through stored history of a container
+type replayState struct {
+	containerID string
+	name        string
+	timeRange   storage.TimeRange
+	points      []storage.DataPoint
+	cursor      int // Index of the newest point shown
+	playing     bool
+	generation  int // Invalidates ticks from earlier play/pause cycles
+}
+
+type replayTickMsg struct {
+	generation int
+}
+
+// replayTick schedules the next playback frame
+func replayTick(generation int) tea.Cmd {
+	return tea.Tick(replayFrameInterval, func(time.Time) tea.Msg {
+		return replayTickMsg{generation: generation}
+	})
+}
+
+// startReplay loads stored history for the selected container and starts playing it
+func (m *Model) startReplay() tea.Cmd {
+	if m.storage == nil || len(m.containers) == 0 {
+		return nil
+	}
+
+	container := m.containers[m.cursor]
+	points, err := m.storage.Query(container.ID, m.timeRange)
+	if err != nil {
+		m.message = fmt.Sprintf("Replay error: %v", err)
+		return nil
+	}
+	if len(points) == 0 {
+		m.message = fmt.Sprintf("No stored history for %s in the last %s", container.Name, m.timeRange)
+		return nil
+	}
+
+	m.replay = &replayState{
+		containerID: container.ID,
+		name:        container.Name,
+		timeRange:   m.timeRange,
+		points:      points,
+		playing:     true,
+	}
+	return replayTick(m.replay.generation)
+}
+
+// step returns how many points one frame or seek unit advances
+func (r *replayState) step() int {
+	step := len(r.points) / replayTargetFrames
+	if step < 1 {
+		step = 1
+	}
+	return step
+}
+
+// seek moves the playback cursor, clamped to the available points
+func (r *replayState) seek(delta int) {
+	r.cursor += delta
+	if r.cursor < 0 {
+		r.cursor = 0
+	}
+	if r.cursor > len(r.points)-1 {
+		r.cursor = len(r.points) - 1
+	}
+}
+
+// handleReplayKey handles playback keys, reporting whether the key was used
+func (m *Model) handleReplayKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	r := m.replay
+
+	switch msg.String() {
+	case "p", "esc":
+		m.replay = nil
+		return nil, true
+
+	case " ":
+		r.playing = !r.playing
+		r.generation++
+		if r.playing {
+			// Playing from the end starts over
+			if r.cursor >= len(r.points)-1 {
+				r.cursor = 0
+			}
+			return replayTick(r.generation), true
+		}
+		return nil, true
+
+	case "left", "h":
+		r.seek(-r.step())
+	case "right", "l":
+		r.seek(r.step())
+	case "<", ",":
+		r.seek(-max(len(r.points)/10, 1))
+	case ">", ".":
+		r.seek(max(len(r.points)/10, 1))
+	case "home":
+		r.cursor = 0
+	case "end":
+		r.cursor = len(r.points) - 1
+
+	default:
+		return nil, false
+	}
+
+	return nil, true
+}
+
+// advanceReplay moves playback forward one frame
+func (m *Model) advanceReplay(msg replayTickMsg) tea.Cmd {
+	r := m.replay
+	if r == nil || !r.playing || msg.generation != r.generation {
+		return nil
+	}
+
+	r.seek(r.step())
+	if r.cursor >= len(r.points)-1 {
+		r.playing = false
+		return nil
+	}
+	return replayTick(r.generation)
+}
+
+// renderReplayGraph renders the graph up to the playback cursor with the playback time
+func renderReplayGraph(r *replayState, width, height int, opts graphOptions) string {
+	var s strings.Builder
+
+	title := fmt.Sprintf("⏯ Replay: %s - %s", r.name, r.timeRange.String())
+	s.WriteString(graphTitleStyle.Render(title) + "\n")
+	hint := "[space] play/pause [←/→] step [</>] seek [p] exit"
+	s.WriteString(graphAxisStyle.Render(hint) + "\n\n")
+
+	// Playback position
+	current := r.points[r.cursor]
+	state := "⏸"
+	if r.playing {
+		state = "▶"
+	}
+	stamp := fmt.Sprintf("%s %s", state, current.Timestamp.Format("2006-01-02 15:04:05"))
+	s.WriteString(replayTimestampStyle.Render(stamp))
+	s.WriteString(graphAxisStyle.Render(fmt.Sprintf("  %d/%d", r.cursor+1, len(r.points))) + "\n")
+	s.WriteString(renderReplayProgress(r, width-8) + "\n\n")
+
+	cpuData := make([]float64, r.cursor+1)
+	memData := make([]float64, r.cursor+1)
+	for i, dp := range r.points[:r.cursor+1] {
+		cpuData[i] = dp.CPUPercent
+		memData[i] = dp.MemoryPercent
+	}
+
+	graphHeight := height - 17
+	if graphHeight < 5 {
+		graphHeight = 5
+	}
+	s.WriteString(renderCombinedGraph(cpuData, memData, width-8, graphHeight, opts))
+
+	return s.String()
+}
+
+// renderReplayProgress renders a seek bar marking the playback position
+func renderReplayProgress(r *replayState, width int) string {
+	if width < 2 {
+		width = 2
+	}
+	pos := 0
+	if len(r.points) > 1 {
+		pos = r.cursor * (width - 1) / (len(r.points) - 1)
+	}
+	return graphAxisStyle.Render(strings.Repeat("─", pos)) +
+		replayTimestampStyle.UnsetPadding().UnsetBackground().Render("●") +
+		graphAxisStyle.Render(strings.Repeat("─", width-1-pos))
+}
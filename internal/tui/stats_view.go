@@ -8,8 +8,17 @@ import (
 	"github.com/rusenback/docker-monitor/internal/model"
 )
 
-// RenderStats renders the statistics for a container
-func RenderStats(container *model.Container, stats *model.Stats) string {
+// processTable describes the visible window and selection of the processes table
+type processTable struct {
+	cursor  int  // Index of the selected process
+	rows    int  // Number of process rows that fit, 0 shows all
+	width   int  // Available width for the selected process details
+	focused bool // Highlight the selection only when the stats panel has focus
+}
+
+// RenderStats renders the statistics for a container, excluding the processes table.
+// A non-zero coreBasis shows CPU usage in cores out of that many cores.
+func RenderStats(container *model.Container, stats *model.Stats, coreBasis float64) string {
 	if stats == nil {
 		return helpStyle.Render("No stats available")
 	}
@@ -41,29 +50,23 @@ func RenderStats(container *model.Container, stats *model.Stats) string {
 		return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(text)
 	}
 
-	formatBytes := func(b uint64) string {
-		switch {
-		case b > 1_000_000_000:
-			return fmt.Sprintf("%.2f GB", float64(b)/1_000_000_000)
-		case b > 1_000_000:
-			return fmt.Sprintf("%.2f MB", float64(b)/1_000_000)
-		case b > 1_000:
-			return fmt.Sprintf("%.2f KB", float64(b)/1_000)
-		default:
-			return fmt.Sprintf("%d B", b)
-		}
-	}
-
 	barLength := 30 // wider bar for vertical layout
 
 	// CPU box
 	cpuBar := renderBar(stats.CPUPercent, barLength)
 	cpuStr := fmt.Sprintf("%6.2f%% |%s|", stats.CPUPercent, cpuBar)
+	cpuLevel := stats.CPUPercent
+	if coreBasis > 0 {
+		// Fill the bar relative to the available cores rather than one core
+		cpuLevel = stats.CPUPercent / coreBasis
+		cpuBar = renderBar(cpuLevel, barLength)
+		cpuStr = fmt.Sprintf("%5.2f / %.1f cores |%s|", stats.CPUPercent/100, coreBasis, cpuBar)
+	}
 	cpuBox := lipgloss.NewStyle().
 		Border(lipgloss.NormalBorder()).
 		BorderForeground(lipgloss.Color("#89B4FA")).
 		Padding(0, 1).
-		Render("CPU\n" + colorize(stats.CPUPercent, cpuStr))
+		Render("CPU\n" + colorize(cpuLevel, cpuStr))
 
 	// Memory box
 	memBar := renderBar(stats.MemoryPercent, barLength)
@@ -101,9 +104,6 @@ func RenderStats(container *model.Container, stats *model.Stats) string {
 		Foreground(lipgloss.Color("#F5C2E7")).
 		Render("Container: " + container.Name)
 
-	// Top Processes
-	processesSection := renderProcesses(stats.Processes)
-
 	// Build final layout vertically
 	result := lipgloss.JoinVertical(lipgloss.Left,
 		title,
@@ -112,14 +112,13 @@ func RenderStats(container *model.Container, stats *model.Stats) string {
 		pidsStr,
 		netStr,
 		blockStr,
-		processesSection,
 	)
 
 	return result
 }
 
-// renderProcesses renders the top processes table
-func renderProcesses(processes []model.Process) string {
+// renderProcesses renders the top processes table, scrolled so the selected row is visible
+func renderProcesses(processes []model.Process, table processTable) string {
 	if len(processes) == 0 {
 		return ""
 	}
@@ -127,11 +126,32 @@ func renderProcesses(processes []model.Process) string {
 	var s strings.Builder
 	s.WriteString("\n")
 
-	// Title
+	// Work out which rows fit, keeping the cursor in view
+	rows := table.rows
+	if rows <= 0 || rows > len(processes) {
+		rows = len(processes)
+	}
+	cursor := table.cursor
+	if cursor >= len(processes) {
+		cursor = len(processes) - 1
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	offset := 0
+	if cursor >= rows {
+		offset = cursor - rows + 1
+	}
+
+	// Title, with position when not everything fits
+	titleText := "Top Processes"
+	if rows < len(processes) {
+		titleText = fmt.Sprintf("Top Processes [%d-%d/%d]", offset+1, offset+rows, len(processes))
+	}
 	procTitle := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("#F9E2AF")).
-		Render("Top Processes")
+		Render(titleText)
 	s.WriteString(procTitle + "\n")
 
 	// Header
@@ -145,7 +165,9 @@ func renderProcesses(processes []model.Process) string {
 
 	// Process rows
 	rowStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#CDD6F4"))
-	for _, proc := range processes {
+	for i := offset; i < offset+rows; i++ {
+		proc := processes[i]
+
 		// Truncate command if too long
 		cmd := proc.Command
 		if len(cmd) > 40 {
@@ -158,7 +180,21 @@ func renderProcesses(processes []model.Process) string {
 			truncateStr(proc.CPU, 6),
 			truncateStr(proc.Memory, 6),
 			cmd)
-		s.WriteString(rowStyle.Render(row) + "\n")
+		if table.focused && i == cursor {
+			s.WriteString(selectedStyle.Render(row) + "\n")
+		} else {
+			s.WriteString(rowStyle.Render(row) + "\n")
+		}
+	}
+
+	// Full command of the selected process, which the table usually cuts off
+	if table.focused {
+		selected := processes[cursor]
+		detail := fmt.Sprintf("PID %s (%s): %s", selected.PID, selected.User, selected.Command)
+		if table.width > 3 {
+			detail = truncate(detail, table.width)
+		}
+		s.WriteString(helpStyle.UnsetPadding().Render(detail) + "\n")
 	}
 
 	return s.String()
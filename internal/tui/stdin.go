@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"io"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// stdinBacklog is how many typed lines may wait to be written to a container
+const stdinBacklog = 64
+
+// stdinWriter writes lines to an attached stdin from a single goroutine, so
+// they reach the container in the order they were typed
+type stdinWriter struct {
+	containerID string
+	conn        io.WriteCloser
+	lines       chan string
+	done        chan error // Receives the write error, or nil once closed
+}
+
+func newStdinWriter(containerID string, conn io.WriteCloser) *stdinWriter {
+	w := &stdinWriter{
+		containerID: containerID,
+		conn:        conn,
+		lines:       make(chan string, stdinBacklog),
+		done:        make(chan error, 1),
+	}
+	go w.run()
+	return w
+}
+
+func (w *stdinWriter) run() {
+	for line := range w.lines {
+		if _, err := io.WriteString(w.conn, line+"\n"); err != nil {
+			w.done <- err
+			// Discard the rest until the writer is closed
+			for range w.lines {
+			}
+			return
+		}
+	}
+	w.done <- nil
+}
+
+// send queues a line, reporting false when too many lines are waiting
+func (w *stdinWriter) send(line string) bool {
+	select {
+	case w.lines <- line:
+		return true
+	default:
+		return false
+	}
+}
+
+// close detaches from the container. Lines still waiting are dropped.
+func (w *stdinWriter) close() {
+	close(w.lines)
+	w.conn.Close()
+}
+
+// waitForStdin creates a command that reports when a stdin writer stops
+func waitForStdin(w *stdinWriter) tea.Cmd {
+	return func() tea.Msg {
+		return stdinDoneMsg{containerID: w.containerID, writer: w, err: <-w.done}
+	}
+}
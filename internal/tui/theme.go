@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rusenback/docker-monitor/internal/config"
+)
+
+// graphTheme holds the colors and characters of the combined CPU/memory graph
+type graphTheme struct {
+	cpu         lipgloss.Style
+	memory      lipgloss.Style
+	overlap     lipgloss.Style
+	cpuChar     string
+	memoryChar  string
+	overlapChar string
+}
+
+var graphThemes = map[string]graphTheme{
+	config.ThemeDefault: {
+		cpu:         lipgloss.NewStyle().Foreground(lipgloss.Color("#89B4FA")), // Blue
+		memory:      lipgloss.NewStyle().Foreground(lipgloss.Color("#A6E3A1")), // Green
+		overlap:     lipgloss.NewStyle().Foreground(lipgloss.Color("#CBA6F7")), // Purple
+		cpuChar:     "█",
+		memoryChar:  "█",
+		overlapChar: "█",
+	},
+	// Okabe-Ito blue/orange stay distinguishable with the common forms of
+	// color blindness, and distinct characters do not rely on color at all
+	config.ThemeColorblind: {
+		cpu:         lipgloss.NewStyle().Foreground(lipgloss.Color("#0072B2")), // Blue
+		memory:      lipgloss.NewStyle().Foreground(lipgloss.Color("#E69F00")), // Orange
+		overlap:     lipgloss.NewStyle().Foreground(lipgloss.Color("#F0E442")), // Yellow
+		cpuChar:     "█",
+		memoryChar:  "▒",
+		overlapChar: "▚",
+	},
+}
+
+// newGraphTheme returns the named theme with any per-color overrides applied
+func newGraphTheme(name string, overrides config.GraphColors) graphTheme {
+	theme, ok := graphThemes[name]
+	if !ok {
+		theme = graphThemes[config.ThemeDefault]
+	}
+
+	if overrides.CPU != "" {
+		theme.cpu = lipgloss.NewStyle().Foreground(lipgloss.Color(overrides.CPU))
+	}
+	if overrides.Memory != "" {
+		theme.memory = lipgloss.NewStyle().Foreground(lipgloss.Color(overrides.Memory))
+	}
+	if overrides.Overlap != "" {
+		theme.overlap = lipgloss.NewStyle().Foreground(lipgloss.Color(overrides.Overlap))
+	}
+	theme.cpuChar = graphChar(overrides.CPUChar, theme.cpuChar)
+	theme.memoryChar = graphChar(overrides.MemoryChar, theme.memoryChar)
+	theme.overlapChar = graphChar(overrides.OverlapChar, theme.overlapChar)
+
+	return theme
+}
+
+// graphChar returns an override character if it fills exactly one cell, so
+// the graph columns stay aligned, and the theme's character otherwise
+func graphChar(override, fallback string) string {
+	if lipgloss.Width(override) != 1 {
+		return fallback
+	}
+	return override
+}
@@ -0,0 +1,29 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/rusenback/docker-monitor/internal/config"
+)
+
+func TestNewGraphThemeChars(t *testing.T) {
+	defaults := graphThemes[config.ThemeDefault]
+
+	tests := []struct {
+		override string
+		want     string
+	}{
+		{"", defaults.cpuChar},
+		{"▞", "▞"},
+		{"#", "#"},
+		{"##", defaults.cpuChar},
+		{"🔥", defaults.cpuChar}, // Two cells wide
+	}
+
+	for _, tt := range tests {
+		theme := newGraphTheme(config.ThemeDefault, config.GraphColors{CPUChar: tt.override})
+		if theme.cpuChar != tt.want {
+			t.Errorf("cpu_char %q gives %q, want %q", tt.override, theme.cpuChar, tt.want)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Block colors, cycled so neighbouring blocks differ
+var treemapColors = []lipgloss.Color{
+	"#89B4FA", "#A6E3A1", "#F9E2AF", "#FAB387", "#CBA6F7", "#94E2D5", "#F5C2E7", "#74C7EC",
+}
+
+// treemapItem is one container in the treemap
+type treemapItem struct {
+	name  string
+	value float64
+	label string // Formatted value shown under the name
+}
+
+// treemapRect is an area of the terminal grid in cells
+type treemapRect struct {
+	x, y, w, h int
+}
+
+// treemapItems builds the treemap items for the running containers from the
+// latest stats snapshot, largest first, and counts the idle ones left out
+func (m Model) treemapItems() ([]treemapItem, int) {
+	var items []treemapItem
+	idle := 0
+	for _, c := range m.allContainers {
+		stats := m.snapshots[c.ID]
+		if c.State != "running" || stats == nil {
+			continue
+		}
+
+		item := treemapItem{name: m.displayName(c)}
+		if m.treemapMemory {
+			item.value = float64(stats.MemoryUsage)
+			item.label = formatBytes(stats.MemoryUsage)
+		} else {
+			item.value = stats.CPUPercent
+			item.label = fmt.Sprintf("%.1f%%", stats.CPUPercent)
+		}
+
+		if item.value <= 0 {
+			idle++
+			continue
+		}
+		items = append(items, item)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].value > items[j].value
+	})
+	return items, idle
+}
+
+// layoutTreemap splits an area between values sorted largest first. Each step
+// divides the values into two groups of about equal total and cuts the area
+// across its longer side in proportion. Values that no longer fit get an
+// empty rectangle.
+func layoutTreemap(values []float64, area treemapRect) []treemapRect {
+	rects := make([]treemapRect, len(values))
+	splitTreemap(values, area, rects)
+	return rects
+}
+
+func splitTreemap(values []float64, area treemapRect, rects []treemapRect) {
+	if len(values) == 0 || area.w <= 0 || area.h <= 0 {
+		return
+	}
+	if len(values) == 1 {
+		rects[0] = area
+		return
+	}
+
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+
+	// Find the split point closest to half of the total
+	k, sum := 1, values[0]
+	for k < len(values)-1 && sum+values[k]/2 < total/2 {
+		sum += values[k]
+		k++
+	}
+
+	first, second := area, area
+	// Terminal cells are about twice as tall as they are wide
+	if area.w >= area.h*2 {
+		if area.w < 2 {
+			rects[0] = area
+			return
+		}
+		cut := min(max(int(float64(area.w)*sum/total+0.5), 1), area.w-1)
+		first.w = cut
+		second.x += cut
+		second.w -= cut
+	} else {
+		if area.h < 2 {
+			rects[0] = area
+			return
+		}
+		cut := min(max(int(float64(area.h)*sum/total+0.5), 1), area.h-1)
+		first.h = cut
+		second.y += cut
+		second.h -= cut
+	}
+
+	splitTreemap(values[:k], first, rects[:k])
+	splitTreemap(values[k:], second, rects[k:])
+}
+
+// renderTreemap renders containers as blocks sized by their usage
+func renderTreemap(items []treemapItem, idle int, memory bool, width, height int) string {
+	var s strings.Builder
+
+	metric, other := "CPU", "memory"
+	if memory {
+		metric, other = "Memory", "CPU"
+	}
+	s.WriteString(graphTitleStyle.Render("🧱 Usage Treemap - "+metric) + "\n")
+	hint := fmt.Sprintf("[o] graph [O] %s", other)
+	if idle > 0 {
+		hint += fmt.Sprintf(" | %d idle not shown", idle)
+	}
+	s.WriteString(graphAxisStyle.Render(hint) + "\n\n")
+
+	if len(items) == 0 {
+		s.WriteString("Waiting for data...")
+		return s.String()
+	}
+
+	// Leave room for the panel padding and the title lines
+	gridWidth := width - 4
+	gridHeight := height - 5
+	if gridWidth < 1 || gridHeight < 1 {
+		return s.String()
+	}
+
+	values := make([]float64, len(items))
+	for i, item := range items {
+		values[i] = item.value
+	}
+	rects := layoutTreemap(values, treemapRect{w: gridWidth, h: gridHeight})
+
+	owner := make([][]int, gridHeight)
+	cells := make([][]rune, gridHeight)
+	for y := range owner {
+		owner[y] = make([]int, gridWidth)
+		cells[y] = []rune(strings.Repeat(" ", gridWidth))
+		for x := range owner[y] {
+			owner[y][x] = -1
+		}
+	}
+
+	for i, r := range rects {
+		for y := r.y; y < r.y+r.h; y++ {
+			for x := r.x; x < r.x+r.w; x++ {
+				owner[y][x] = i
+			}
+		}
+
+		// Only blocks big enough to read get a label
+		if r.w < 4 {
+			continue
+		}
+		lines := []string{items[i].name, items[i].label}
+		for line := 0; line < len(lines) && line < r.h; line++ {
+			text := []rune(truncate(lines[line], r.w))
+			copy(cells[r.y+line][r.x:r.x+r.w], text)
+		}
+	}
+
+	for y := range owner {
+		start := 0
+		for x := 1; x <= gridWidth; x++ {
+			if x < gridWidth && owner[y][x] == owner[y][start] {
+				continue
+			}
+			run := string(cells[y][start:x])
+			if i := owner[y][start]; i >= 0 {
+				color := treemapColors[i%len(treemapColors)]
+				run = lipgloss.NewStyle().Background(color).Foreground(lipgloss.Color("#1E1E2E")).Render(run)
+			}
+			s.WriteString(run)
+			start = x
+		}
+		if y < gridHeight-1 {
+			s.WriteString("\n")
+		}
+	}
+
+	return s.String()
+}
@@ -1,10 +1,14 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rusenback/docker-monitor/internal/config"
+	"github.com/rusenback/docker-monitor/internal/docker"
 	"github.com/rusenback/docker-monitor/internal/model"
 	"github.com/rusenback/docker-monitor/internal/storage"
 )
@@ -17,6 +21,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 
 	case tea.KeyMsg:
+		if m.prompt != nil {
+			return m.handlePromptKey(msg)
+		}
+		if m.showInspect {
+			if cmd, handled := m.handleInspectKey(msg); handled {
+				return m, cmd
+			}
+		}
+		if m.showCompare {
+			if cmd, handled := m.handleCompareKey(msg); handled {
+				return m, cmd
+			}
+		}
+		if m.replay != nil {
+			if cmd, handled := m.handleReplayKey(msg); handled {
+				return m, cmd
+			}
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			if m.statsCancel != nil {
@@ -25,15 +48,32 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.logsCancel != nil {
 				m.logsCancel()
 			}
+			// Store ongoing breaches before the program exits
+			if record := m.closeBreaches(); record != nil {
+				record()
+			}
 			return m, tea.Quit
 
 		case "up", "k":
+			// With the stats panel focused the arrows move through the processes table
+			if m.focusedPanel == PanelStats {
+				if m.processCursor > 0 {
+					m.selectProcess(m.processCursor - 1)
+				}
+				return m, nil
+			}
 			if m.cursor > 0 {
 				m.cursor--
 				return m, m.updateStatsAndLogsForCursor()
 			}
 
 		case "down", "j":
+			if m.focusedPanel == PanelStats {
+				if m.processCursor < len(m.currentProcesses)-1 {
+					m.selectProcess(m.processCursor + 1)
+				}
+				return m, nil
+			}
 			if m.cursor < len(m.containers)-1 {
 				m.cursor++
 				return m, m.updateStatsAndLogsForCursor()
@@ -85,8 +125,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "c":
 			// Clear logs
-			m.logs = []model.LogEntry{}
-			m.logsScroll = 0
+			m.clearLogs()
+
+		case "L":
+			// Load logs from earlier runs and rotated log files
+			if len(m.containers) > 0 {
+				m.message = "Loading earlier logs..."
+				return m, loadEarlierLogs(m.client, m.containers[m.cursor].ID)
+			}
 
 		case "s":
 			if len(m.containers) > 0 {
@@ -95,14 +141,162 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "x":
 			if len(m.containers) > 0 {
+				if m.containers[m.cursor].IsSelf {
+					m.message = "⚠ Not stopping the container dockermon is running in"
+					return m, nil
+				}
 				return m, stopContainer(m.client, m.containers[m.cursor].ID, m.containers[m.cursor].Name)
 			}
 
 		case "r":
 			if len(m.containers) > 0 {
+				if m.containers[m.cursor].IsSelf {
+					m.message = "⚠ Not restarting the container dockermon is running in"
+					return m, nil
+				}
 				return m, restartContainer(m.client, m.containers[m.cursor].ID, m.containers[m.cursor].Name)
 			}
 
+		case "F":
+			// Restart the selected container and follow the logs of its new run
+			if len(m.containers) > 0 {
+				container := m.containers[m.cursor]
+				if container.IsSelf {
+					m.message = "⚠ Not restarting the container dockermon is running in"
+					return m, nil
+				}
+				m.following = container.ID
+				m.message = fmt.Sprintf("Restarting %s...", container.Name)
+				return m, restartAndFollow(m.client, container.ID, container.Name)
+			}
+
+		case "K":
+			// Force-kill a container the watchdog found stuck
+			if m.stuck != nil {
+				stuck := m.stuck
+				m.stuck = nil
+				m.message = fmt.Sprintf("Killing %s...", stuck.name)
+				return m, killContainer(m.client, stuck.id, stuck.name)
+			}
+
+		case "C":
+			// Toggle CPU display between percent and cores, remembering the choice
+			m.cpuCores = !m.cpuCores
+			m.config.CPUUnit = config.CPUPercent
+			if m.cpuCores {
+				m.config.CPUUnit = config.CPUCores
+			}
+			if err := m.config.Save(); err != nil {
+				m.message = fmt.Sprintf("Error: %v", err)
+			}
+
+		case "i":
+			// Toggle between short and full image references
+			m.showFullImage = !m.showFullImage
+
+		case "t":
+			// Edit the tags of the selected container
+			if len(m.containers) > 0 {
+				name := m.containers[m.cursor].Name
+				m.prompt = &promptState{
+					kind:   promptTags,
+					label:  fmt.Sprintf("Tags for %s: ", name),
+					value:  strings.Join(m.config.TagsFor(name), ", "),
+					target: name,
+				}
+			}
+
+		case "T":
+			// Cycle the tag filter: all -> each tag in turn -> all
+			m.tagFilter = nextTag(m.config.AllTags(), m.tagFilter)
+			m.applyFilters()
+			return m, m.updateStatsAndLogsForCursor()
+
+		case "p":
+			// Replay stored history of the selected container
+			return m, m.startReplay()
+
+		case ">":
+			// Send input to the selected container's stdin
+			if len(m.containers) > 0 {
+				container := m.containers[m.cursor]
+				if container.State != "running" {
+					m.message = fmt.Sprintf("%s is not running", container.Name)
+					return m, nil
+				}
+				m.message = fmt.Sprintf("Attaching to %s...", container.Name)
+				return m, attachStdin(m.client, container.ID, container.Name)
+			}
+
+		case "u":
+			// Undo the last start/stop while its toast is showing
+			if m.undo != nil && time.Now().Before(m.undo.expires) {
+				undo := m.undo
+				m.undo = nil
+				if undo.action == "start" {
+					return m, startContainer(m.client, undo.id, undo.name)
+				}
+				return m, stopContainer(m.client, undo.id, undo.name)
+			}
+
+		case "*":
+			// Pin or unpin the selected container for comparison
+			if len(m.containers) > 0 {
+				name := m.containers[m.cursor].Name
+				pinned := m.config.TogglePin(name)
+				if err := m.config.Save(); err != nil {
+					m.message = fmt.Sprintf("Error: %v", err)
+				} else if pinned {
+					m.message = fmt.Sprintf("Pinned %s (%d pinned, V to compare)", name, len(m.config.Pins))
+				} else {
+					m.message = fmt.Sprintf("Unpinned %s", name)
+				}
+			}
+
+		case "D":
+			// Clear all pins
+			m.config.ClearPins()
+			if err := m.config.Save(); err != nil {
+				m.message = fmt.Sprintf("Error: %v", err)
+			} else {
+				m.message = "Cleared all pins"
+			}
+
+		case "V":
+			// Open the comparison view of pinned containers
+			m.showCompare = true
+			return m, m.refreshSnapshot()
+
+		case "n":
+			// Toggle Compose/swarm "service.replica" names, remembering the choice
+			m.config.ServiceNames = !m.config.ServiceNames
+			if err := m.config.Save(); err != nil {
+				m.message = fmt.Sprintf("Error: %v", err)
+			}
+
+		case "o":
+			// Toggle the usage treemap in the graph panel
+			m.showTreemap = !m.showTreemap
+			return m, m.refreshSnapshot()
+
+		case "O":
+			// Switch the treemap between CPU and memory
+			m.treemapMemory = !m.treemapMemory
+			if !m.showTreemap {
+				m.showTreemap = true
+				return m, m.refreshSnapshot()
+			}
+
+		case "enter":
+			// Open the inspect overlay for the selected container
+			if len(m.containers) > 0 {
+				m.showInspect = true
+			}
+
+		case "b":
+			// Toggle the limit breach history in the stats panel
+			m.showBreaches = !m.showBreaches
+
 		case "R":
 			m.loading = true
 			m.message = "Refreshing..."
@@ -128,6 +322,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.focusedPanel = (m.focusedPanel + 3) % 4 // +3 is same as -1 in mod 4
 		}
 
+	case replayTickMsg:
+		return m, m.advanceReplay(msg)
+
 	case tickMsg:
 		return m, tea.Batch(fetchContainers(m.client), tickCmd())
 
@@ -138,35 +335,125 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		previous := m.containers
+		m.allContainers = msg.containers
+		m.multipleProjects = hasMultipleProjects(m.allContainers)
+		if !m.pinsRestored {
+			m.pinsRestored = true
+			m.restorePins()
+		}
+		m.markSelf()
+		m.applyFilters()
+		m.reconcileStuck()
+
 		// Check if container list actually changed
-		containersChanged := containersListChanged(m.containers, msg.containers)
+		containersChanged := containersListChanged(previous, m.containers)
 
-		m.containers = msg.containers
-		if m.cursor >= len(m.containers) && len(m.containers) > 0 {
-			m.cursor = len(m.containers) - 1
-		}
+		snapshot := m.refreshSnapshot()
 
-		// Only update stats/logs if containers changed or cursor container changed
-		if containersChanged {
-			return m, m.updateStatsAndLogsForCursor()
+		// Only update stats/logs if containers changed or a stream needs reopening
+		if containersChanged || m.logsCancel == nil || m.statsCancel == nil {
+			return m, tea.Batch(snapshot, m.updateStatsAndLogsForCursor())
 		}
 
-		return m, nil
+		return m, snapshot
+
+	case snapshotMsg:
+		m.snapshotting = false
+		m.snapshots = msg.stats
 
 	case actionMsg:
+		if msg.err != nil && msg.action == "stop" && docker.IsTimeout(msg.err) {
+			// The stop may still complete after our timeout, so check again before reporting failure
+			m.message = fmt.Sprintf("Stopping %s timed out, checking whether it stopped...", msg.name)
+			return m, tea.Batch(fetchContainers(m.client), watchdogCheck(m.client, msg.containerID, msg.name))
+		}
 		if msg.err != nil {
+			if msg.action == "follow" {
+				// Keep the logs that may explain why the restart failed
+				m.following = ""
+			}
 			m.message = fmt.Sprintf("Error: %v", msg.err)
-		} else {
-			m.message = msg.message
+			return m, fetchContainers(m.client)
+		}
+
+		if msg.action == "follow" {
+			return m, m.followNewRun(msg.containerID, msg.name)
+		}
+
+		// Starting and stopping are safely reversible, so offer to undo them
+		inverse := map[string]string{"stop": "start", "start": "stop"}[msg.action]
+		if inverse == "" {
+			return m, tea.Batch(fetchContainers(m.client), m.flashMessage(msg.message, messageTimeout))
+		}
+		m.undo = &undoAction{
+			action:  inverse,
+			id:      msg.containerID,
+			name:    msg.name,
+			expires: time.Now().Add(undoWindow),
+		}
+		toast := fmt.Sprintf("%s - press u to %s again", msg.message, inverse)
+		return m, tea.Batch(fetchContainers(m.client), m.flashMessage(toast, undoWindow))
+
+	case flapGraceMsg:
+		if m.flap == nil || m.flap.id != msg.containerID || !m.flap.until.Equal(msg.until) {
+			// The container came back, or a newer grace period replaced this one
+			return m, nil
+		}
+		m.flap = nil
+		if msg.containerID == m.currentContainerID && len(m.containers) > 0 &&
+			m.containers[m.cursor].State != "running" {
+			return m, m.stopStats()
+		}
+
+	case clearMessageMsg:
+		// Leave newer messages alone
+		if m.message == msg.text {
+			m.message = ""
+		}
+
+	case watchdogMsg:
+		switch {
+		case msg.err != nil:
+			m.message = fmt.Sprintf("Stopping %s timed out and its state could not be checked: %v", msg.name, msg.err)
+		case msg.info.Running:
+			m.stuck = &stuckContainer{id: msg.containerID, name: msg.name}
+			m.message = m.stuckMessage()
+		default:
+			m.message = fmt.Sprintf("Stop timed out, but %s has since stopped (%s)", msg.name, msg.info.State)
 		}
 		return m, fetchContainers(m.client)
 
 	case statsMsg:
-		if msg.err != nil {
-			m.message = fmt.Sprintf("Stats error: %v", msg.err)
+		if msg.containerID != m.currentContainerID {
+			// Stale message from a stream we already stopped
+			return m, nil
+		}
+		if msg.ended {
+			// Reopened by updateStatsAndLogsForCursor once the container runs again
+			if m.statsCancel != nil {
+				m.statsCancel()
+				m.statsCancel = nil
+			}
+			return m, m.closeBreaches()
+		}
+		if errors.Is(msg.err, docker.ErrNoStats) && m.currentStats != nil {
+			// Keep the last sample for a moment in case the container comes right back
+			return m, tea.Batch(m.startFlapGrace(msg.containerID), waitForStats(msg.containerID, m.statsChan, m.statsErrChan))
+		}
+		if errors.Is(msg.err, docker.ErrNoStats) {
+			m.currentStats = nil
+			m.statsNote = "No stats available - the container is not running"
+		} else if msg.err != nil {
+			m.currentStats = nil
+			m.statsNote = fmt.Sprintf("Stats error: %v", msg.err)
 		} else {
 			m.currentStats = msg.stats
-			m.message = ""
+			m.statsNote = ""
+			if time.Now().After(m.messageUntil) {
+				// The force-kill offer stays until the stuck container is dealt with
+				m.message = m.stuckMessage()
+			}
 
 			// Store historical data for graphs (shift left and add new value)
 			if msg.stats != nil {
@@ -197,30 +484,152 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Update processes if they were fetched
 				if len(msg.stats.Processes) > 0 {
 					m.currentProcesses = msg.stats.Processes
+					m.followSelectedProcess()
 				}
 			}
 		}
-		return m, waitForStats(m.statsChan, m.statsErrChan)
+		var breachCmd tea.Cmd
+		if msg.err == nil && msg.stats != nil {
+			breachCmd = m.trackBreaches(msg.stats)
+		}
+		return m, tea.Batch(waitForStats(msg.containerID, m.statsChan, m.statsErrChan), breachCmd)
 
 	case logsMsg:
+		if msg.containerID != m.currentContainerID || msg.stream != m.logsChan {
+			// Stale message from a stream we already stopped
+			return m, nil
+		}
+		if msg.ended {
+			// The stream closes when the container stops; it is reopened
+			// once the container is seen running again
+			if m.logsCancel != nil {
+				m.logsCancel()
+				m.logsCancel = nil
+			}
+			m.logsChan = nil
+			m.logsErrChan = nil
+			return m, nil
+		}
 		if msg.err != nil {
 			m.message = fmt.Sprintf("Logs error: %v", msg.err)
 		} else {
 			// Only append if the log entry has a message
 			if msg.entry.Message != "" {
-				m.logs = append(m.logs, msg.entry)
-				if len(m.logs) > 1000 {
-					m.logs = m.logs[len(m.logs)-1000:]
-				}
-
-				// Auto-scroll
-				if m.logsAutoScroll {
-					m.logsScroll = m.calculateMaxScroll()
-				}
+				m.appendLog(msg.entry)
 			}
 		}
 		// Keep waiting for the next log line
 		return m, m.waitForLogs()
+
+	case scanMsg:
+		m.scans[msg.key] = &imageScan{result: msg.result, err: msg.err}
+
+	case stdinAttachedMsg:
+		if msg.err != nil {
+			m.message = fmt.Sprintf("Input unavailable for %s: %v", msg.name, msg.err)
+			return m, nil
+		}
+		if msg.containerID != m.currentContainerID {
+			msg.stdin.Close()
+			return m, nil
+		}
+		m.closeStdin()
+		m.stdin = newStdinWriter(msg.containerID, msg.stdin)
+		m.message = ""
+		if m.runInfo != nil && m.runInfo.StdinOnce {
+			m.message = fmt.Sprintf("⚠ %s closes its stdin when input mode ends", msg.name)
+		}
+		m.prompt = &promptState{
+			kind:   promptStdin,
+			label:  fmt.Sprintf("%s stdin> ", msg.name),
+			target: msg.name,
+		}
+		return m, waitForStdin(m.stdin)
+
+	case stdinDoneMsg:
+		if msg.writer != m.stdin || msg.containerID != m.currentContainerID {
+			// A writer we already closed, possibly for another container
+			return m, nil
+		}
+		if msg.err != nil {
+			m.message = fmt.Sprintf("Input error: %v", msg.err)
+			m.closeStdin()
+			m.prompt = nil
+		}
+
+	case breachesMsg:
+		if msg.err != nil {
+			m.message = fmt.Sprintf("Breach history error: %v", msg.err)
+		} else if msg.containerID == m.currentContainerID {
+			m.breaches = msg.events
+		}
+
+	case runInfoMsg:
+		if msg.containerID == m.currentContainerID && msg.err == nil {
+			m.runInfo = msg.info
+		}
+
+	case followReadyMsg:
+		if msg.containerID != m.following {
+			return m, nil
+		}
+		m.following = ""
+		if msg.containerID != m.currentContainerID {
+			// The user moved on while the container was coming back
+			return m, nil
+		}
+		if msg.err != nil {
+			m.message = fmt.Sprintf("%s did not come back up: %v", msg.name, msg.err)
+			return m, fetchContainers(m.client)
+		}
+		m.runInfo = msg.info
+		m.message = fmt.Sprintf("Following new run of %s", msg.name)
+		return m, tea.Batch(m.startLogStreamSince(msg.containerID, msg.info.StartedAt), fetchContainers(m.client))
+
+	case logsResumeMsg:
+		m.logsResuming = false
+		if msg.containerID != m.currentContainerID || m.logsCancel != nil {
+			return m, nil
+		}
+		if msg.err == nil {
+			// A newer start time means the container began a new run since we
+			// last looked. Without an earlier inspect there is nothing to compare.
+			if m.runInfo != nil && msg.info.StartedAt.After(m.runInfo.StartedAt) {
+				m.appendLog(model.LogEntry{
+					Timestamp: time.Now(),
+					Message:   "--- container restarted ---",
+					Stream:    model.StreamMarker,
+				})
+			}
+			m.runInfo = msg.info
+		}
+		return m, m.startLogStream(msg.containerID)
+
+	case earlierLogsMsg:
+		if msg.containerID != m.currentContainerID {
+			return m, nil
+		}
+		if msg.err != nil && len(msg.archived) == 0 {
+			m.message = fmt.Sprintf("Logs error: %v", msg.err)
+			return m, nil
+		}
+		archived, history := m.mergeEarlierLogs(msg.archived, msg.history)
+
+		restarts := 0
+		if m.runInfo != nil {
+			restarts = m.runInfo.RestartCount
+		}
+		switch {
+		case msg.err != nil:
+			m.message = fmt.Sprintf("Loaded %d archived log lines, but current logs failed: %v", archived, msg.err)
+		case msg.archiveErr == nil:
+			m.message = fmt.Sprintf("Loaded %d archived and %d current log lines", archived, history)
+		case restarts > 0:
+			m.message = fmt.Sprintf("Only the current log file is available (%d restarts, earlier runs may be missing): %v",
+				restarts, msg.archiveErr)
+		default:
+			m.message = fmt.Sprintf("Only the current log file is available: %v", msg.archiveErr)
+		}
 	}
 
 	return m, nil
@@ -229,7 +638,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 // updateStatsAndLogsForCursor updates stats and logs streaming when the cursor changes
 func (m *Model) updateStatsAndLogsForCursor() tea.Cmd {
 	if len(m.containers) == 0 {
-		return nil
+		// A filter may hide the followed container, so stop following it
+		return m.clearSelection()
 	}
 
 	container := m.containers[m.cursor]
@@ -241,6 +651,8 @@ func (m *Model) updateStatsAndLogsForCursor() tea.Cmd {
 
 	// --- Stats streaming ---
 	if container.State == "running" {
+		m.flap = nil
+
 		// Only restart stats stream if container changed
 		if containerChanged || m.statsCancel == nil {
 			if m.statsCancel != nil {
@@ -250,14 +662,15 @@ func (m *Model) updateStatsAndLogsForCursor() tea.Cmd {
 			m.statsCancel = cancel
 			m.statsChan = statsChan
 			m.statsErrChan = errChan
-			cmds = append(cmds, waitForStats(statsChan, errChan))
+			m.statsNote = ""
+			cmds = append(cmds, waitForStats(container.ID, statsChan, errChan))
 		}
+	} else if !containerChanged && m.currentStats != nil {
+		// Keep the panels for a moment in case the container comes right back
+		cmds = append(cmds, m.startFlapGrace(container.ID))
 	} else {
-		if m.statsCancel != nil {
-			m.statsCancel()
-			m.statsCancel = nil
-		}
-		m.currentStats = nil
+		m.flap = nil
+		cmds = append(cmds, m.stopStats())
 	}
 
 	// --- Logs streaming ---
@@ -271,44 +684,441 @@ func (m *Model) updateStatsAndLogsForCursor() tea.Cmd {
 			m.logsErrChan = nil
 		}
 
+		// Replay shows the history of the previous container
+		if m.replay != nil && m.replay.containerID != container.ID {
+			m.replay = nil
+		}
+
+		// Input mode belongs to the previous container
+		m.closeStdin()
+		if m.prompt != nil && m.prompt.kind == promptStdin {
+			m.prompt = nil
+		}
+
 		// Reset logs and enable autoscroll for new container
 		m.logs = []model.LogEntry{}
 		m.logsScroll = 0
 		m.logsAutoScroll = true
+		m.logsResuming = false
+		m.runInfo = nil
 
 		// Clear historical graph data for new container (pre-filled with zeros)
 		m.cpuHistory = make([]float64, m.maxDataPoints)
 		m.memoryHistory = make([]float64, m.maxDataPoints)
 		m.currentProcesses = nil
+		m.processCursor = 0
+		m.selectedPID = ""
+
+		// Breaches belong to the previous container
+		cmds = append(cmds, m.closeBreaches(), loadBreaches(m.storage, container.ID))
+		m.breaches = nil
 
 		if container.State == "running" {
-			logsChan, errChan, cancel := m.client.StreamContainerLogs(container.ID)
-			m.logsCancel = cancel
-			m.logsChan = logsChan
-			m.logsErrChan = errChan
-			cmds = append(cmds, waitForLogs(logsChan, errChan))
+			cmds = append(cmds, m.startLogStream(container.ID))
 		}
+		cmds = append(cmds, fetchRunInfo(m.client, container.ID))
 
 		// Update the current container ID
 		m.currentContainerID = container.ID
+	} else if container.State == "running" && m.logsCancel == nil && !m.logsResuming && m.following != container.ID {
+		// The log stream ended (or never started) while the container was
+		// stopped. Inspect it first so a restart can be annotated in the logs.
+		m.logsResuming = true
+		cmds = append(cmds, resumeLogs(m.client, container.ID))
 	}
 
 	return tea.Batch(cmds...)
 }
 
+// flashMessage shows a message that clears itself after the given time
+func (m *Model) flashMessage(text string, ttl time.Duration) tea.Cmd {
+	m.message = text
+	m.messageUntil = time.Now().Add(ttl)
+	return tea.Tick(ttl, func(time.Time) tea.Msg {
+		return clearMessageMsg{text: text}
+	})
+}
+
+// selectProcess moves the processes table selection to a row
+func (m *Model) selectProcess(row int) {
+	m.processCursor = row
+	m.selectedPID = m.currentProcesses[row].PID
+}
+
+// followSelectedProcess keeps the selection on the same process after the
+// table was refreshed and re-sorted. When that process is gone the selection
+// stays on the same row, or the last one.
+func (m *Model) followSelectedProcess() {
+	for i, p := range m.currentProcesses {
+		if p.PID == m.selectedPID {
+			m.processCursor = i
+			return
+		}
+	}
+	m.selectProcess(min(m.processCursor, len(m.currentProcesses)-1))
+}
+
+// stopStats closes the stats stream and clears the current sample
+func (m *Model) stopStats() tea.Cmd {
+	if m.statsCancel != nil {
+		m.statsCancel()
+		m.statsCancel = nil
+	}
+	m.currentStats = nil
+	return m.closeBreaches()
+}
+
+// clearSelection stops the streams and breach tracking of the followed
+// container once no container is listed
+func (m *Model) clearSelection() tea.Cmd {
+	if m.currentContainerID == "" {
+		return nil
+	}
+
+	m.flap = nil
+	cmd := m.stopStats()
+	if m.logsCancel != nil {
+		m.logsCancel()
+		m.logsCancel = nil
+		m.logsChan = nil
+		m.logsErrChan = nil
+	}
+	m.logsResuming = false
+	m.replay = nil
+	m.closeStdin()
+	if m.prompt != nil && m.prompt.kind == promptStdin {
+		m.prompt = nil
+	}
+
+	m.clearLogs()
+	m.runInfo = nil
+	m.breaches = nil
+	m.currentProcesses = nil
+	m.currentContainerID = ""
+	return cmd
+}
+
+// startFlapGrace begins the grace period of a container that stopped running,
+// after which its stats are cleared unless it runs again. A grace period
+// already under way is left alone.
+func (m *Model) startFlapGrace(id string) tea.Cmd {
+	if m.flap != nil && m.flap.id == id {
+		return nil
+	}
+	until := time.Now().Add(flapGrace)
+	m.flap = &flapState{id: id, until: until}
+	return tea.Tick(flapGrace, func(time.Time) tea.Msg {
+		return flapGraceMsg{containerID: id, until: until}
+	})
+}
+
+// inFlapGrace reports whether a container is within its grace period after stopping
+func (m Model) inFlapGrace(id string) bool {
+	return m.flap != nil && m.flap.id == id
+}
+
 // waitForLogs creates a command that waits for the next log entry from the model's channels
 func (m *Model) waitForLogs() tea.Cmd {
-	return func() tea.Msg {
-		select {
-		case entry, ok := <-m.logsChan:
-			if !ok {
-				return nil
-			}
-			return logsMsg{entry: entry}
-		case err := <-m.logsErrChan:
-			return logsMsg{err: err}
+	return waitForLogs(m.currentContainerID, m.logsChan, m.logsErrChan)
+}
+
+// startLogStream opens a log stream for a container, continuing after the
+// newest entry already in the buffer when there is one
+func (m *Model) startLogStream(id string) tea.Cmd {
+	var logsChan <-chan model.LogEntry
+	var errChan <-chan error
+	var cancel func()
+
+	if len(m.logs) > 0 {
+		logsChan, errChan, cancel = m.client.StreamContainerLogsSince(id, m.lastLogTimestamp())
+	} else {
+		logsChan, errChan, cancel = m.client.StreamContainerLogs(id)
+	}
+
+	m.logsCancel = cancel
+	m.logsChan = logsChan
+	m.logsErrChan = errChan
+	return waitForLogs(id, logsChan, errChan)
+}
+
+// startLogStreamSince opens a log stream for a container from the given time on
+func (m *Model) startLogStreamSince(id string, since time.Time) tea.Cmd {
+	// The stream skips entries at exactly "since", so start just before it
+	logsChan, errChan, cancel := m.client.StreamContainerLogsSince(id, since.Add(-time.Nanosecond))
+	m.logsCancel = cancel
+	m.logsChan = logsChan
+	m.logsErrChan = errChan
+	return waitForLogs(id, logsChan, errChan)
+}
+
+// followNewRun drops the logs of a restarted container's previous run and
+// waits for the new run before streaming its logs from the start
+func (m *Model) followNewRun(id, name string) tea.Cmd {
+	m.message = fmt.Sprintf("Restarted %s, waiting for container...", name)
+	if id != m.currentContainerID {
+		// The user moved on, so the logs shown belong to another container
+		return waitForRun(m.client, id, name, time.Time{})
+	}
+
+	if m.logsCancel != nil {
+		m.logsCancel()
+		m.logsCancel = nil
+	}
+	m.logsChan = nil
+	m.logsErrChan = nil
+
+	m.clearLogs()
+	m.logsAutoScroll = true
+
+	// The new run is recognized by a start time later than the one we knew
+	var previousStart time.Time
+	if m.runInfo != nil {
+		previousStart = m.runInfo.StartedAt
+	}
+	return waitForRun(m.client, id, name, previousStart)
+}
+
+// clearLogs empties the log buffer
+func (m *Model) clearLogs() {
+	m.logs = []model.LogEntry{}
+	m.logsScroll = 0
+}
+
+// appendLog adds an entry to the log buffer, trimming it and following the tail if enabled
+func (m *Model) appendLog(entry model.LogEntry) {
+	m.logs = insertLog(m.logs, entry)
+	if len(m.logs) > maxLogEntries {
+		m.logs = m.logs[len(m.logs)-maxLogEntries:]
+	}
+
+	// Auto-scroll
+	if m.logsAutoScroll {
+		m.logsScroll = m.calculateMaxScroll()
+	}
+}
+
+// insertLog adds an entry to the buffer in timestamp order. An entry older
+// than the one before it is moved back within the newest logReorderWindow
+// entries, but never past a marker; when that is not far enough it stays at
+// the end and is flagged as out of order.
+func insertLog(logs []model.LogEntry, entry model.LogEntry) []model.LogEntry {
+	// Flagged entries are already out of place, so they do not stop the search
+	goesBefore := func(prev model.LogEntry) bool {
+		return prev.Stream != model.StreamMarker &&
+			(prev.OutOfOrder || prev.Timestamp.After(entry.Timestamp))
+	}
+
+	i := len(logs)
+	if entry.Stream != model.StreamMarker {
+		floor := max(len(logs)-logReorderWindow, 0)
+		for i > floor && goesBefore(logs[i-1]) {
+			i--
+		}
+		// Flagged entries right at the insertion point keep their place before it
+		for i < len(logs) && logs[i].OutOfOrder && !logs[i].Timestamp.After(entry.Timestamp) {
+			i++
+		}
+		// Give up if the entry belongs further back than the window allows
+		if i == floor && floor > 0 && goesBefore(logs[i-1]) {
+			entry.OutOfOrder = true
+			i = len(logs)
+		}
+	}
+
+	logs = append(logs, model.LogEntry{})
+	copy(logs[i+1:], logs[i:])
+	logs[i] = entry
+	return logs
+}
+
+// lastLogTimestamp returns the newest timestamp among the container log
+// entries, which is not necessarily that of the last entry when timestamps
+// went backward
+func (m *Model) lastLogTimestamp() time.Time {
+	return newestTimestamp(m.logs)
+}
+
+// newestTimestamp returns the latest timestamp of non-marker entries
+func newestTimestamp(logs []model.LogEntry) time.Time {
+	var newest time.Time
+	for _, entry := range logs {
+		if entry.Stream != model.StreamMarker && entry.Timestamp.After(newest) {
+			newest = entry.Timestamp
+		}
+	}
+	return newest
+}
+
+// mergeEarlierLogs puts archived and current-run log history in front of the
+// buffer, dropping buffered entries that the history already covers. Archived
+// entries get their share of the buffer even when the current run alone would
+// fill it. It returns how many archived and history entries were kept.
+func (m *Model) mergeEarlierLogs(archived, history []model.LogEntry) (int, int) {
+	history = withoutDuplicates(history, archived)
+	if len(archived) == 0 && len(history) == 0 {
+		return 0, 0
+	}
+
+	cutoff := newestTimestamp(archived)
+	if t := newestTimestamp(history); t.After(cutoff) {
+		cutoff = t
+	}
+	var newer []model.LogEntry
+	for _, entry := range m.logs {
+		if entry.Timestamp.After(cutoff) {
+			newer = append(newer, entry)
+		}
+	}
+
+	// Split what the newer entries leave over, giving archived entries at
+	// least half unless the history does not need its half
+	budget := max(maxLogEntries-len(newer), 0)
+	archivedBudget := min(len(archived), max(budget/2, budget-len(history)))
+	historyBudget := min(len(history), budget-archivedBudget)
+	archived = archived[len(archived)-archivedBudget:]
+	history = history[len(history)-historyBudget:]
+
+	merged := make([]model.LogEntry, 0, len(archived)+len(history)+len(newer))
+	merged = append(merged, archived...)
+	merged = append(merged, history...)
+	merged = append(merged, newer...)
+	if len(merged) > maxLogEntries {
+		merged = merged[len(merged)-maxLogEntries:]
+	}
+	m.logs = merged
+
+	if m.logsAutoScroll {
+		m.logsScroll = m.calculateMaxScroll()
+	}
+	return len(archived), len(history)
+}
+
+// withoutDuplicates returns the entries that do not also appear in seen.
+// Docker's own log reader covers rotated files too, so the current-run
+// history can repeat lines read from the archive.
+func withoutDuplicates(entries, seen []model.LogEntry) []model.LogEntry {
+	if len(seen) == 0 {
+		return entries
+	}
+
+	type key struct {
+		timestamp int64
+		stream    string
+		message   string
+	}
+	known := make(map[key]bool, len(seen))
+	for _, e := range seen {
+		known[key{e.Timestamp.UnixNano(), e.Stream, e.Message}] = true
+	}
+
+	unique := make([]model.LogEntry, 0, len(entries))
+	for _, e := range entries {
+		if !known[key{e.Timestamp.UnixNano(), e.Stream, e.Message}] {
+			unique = append(unique, e)
+		}
+	}
+	return unique
+}
+
+// applyFilters rebuilds the visible container list from allContainers,
+// keeping the cursor on the same container when it is still shown
+func (m *Model) applyFilters() {
+	selectedID := ""
+	if m.cursor < len(m.containers) {
+		selectedID = m.containers[m.cursor].ID
+	}
+
+	hideSelf := m.config.SelfMode() == config.SelfHide
+
+	visible := make([]model.Container, 0, len(m.allContainers))
+	for _, c := range m.allContainers {
+		if hideSelf && c.IsSelf {
+			continue
+		}
+		if m.tagFilter != "" && !m.config.HasTag(c.Name, m.tagFilter) {
+			continue
+		}
+		visible = append(visible, c)
+	}
+	m.containers = visible
+
+	for i, c := range m.containers {
+		if c.ID == selectedID {
+			m.cursor = i
+			return
+		}
+	}
+	if m.cursor >= len(m.containers) && len(m.containers) > 0 {
+		m.cursor = len(m.containers) - 1
+	}
+	if len(m.containers) == 0 {
+		m.cursor = 0
+	}
+}
+
+// markSelf flags the container the monitor is running in
+func (m *Model) markSelf() {
+	if m.selfID == "" {
+		return
+	}
+	for i := range m.allContainers {
+		c := &m.allContainers[i]
+		// selfID may be a full ID, a short ID or a name from DOCKERMON_SELF_ID
+		c.IsSelf = sameContainerID(m.selfID, c.ID) || c.Name == m.selfID
+	}
+}
+
+// minSelfIDLength is the shortest ID prefix accepted as the monitor's own
+// container, the length of the short IDs Docker shows
+const minSelfIDLength = 12
+
+// sameContainerID reports whether two IDs, full or shortened, refer to the
+// same container: one must be a prefix of the other and both long enough
+// not to match by chance
+func sameContainerID(a, b string) bool {
+	if len(a) < minSelfIDLength || len(b) < minSelfIDLength {
+		return false
+	}
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+// nextTag returns the tag after current in tags, or "" after the last one
+func nextTag(tags []string, current string) string {
+	if current == "" {
+		if len(tags) > 0 {
+			return tags[0]
+		}
+		return ""
+	}
+	for i, t := range tags {
+		if t == current && i+1 < len(tags) {
+			return tags[i+1]
+		}
+	}
+	return ""
+}
+
+// stuckMessage offers to force-kill the stuck container, if there is one
+func (m *Model) stuckMessage() string {
+	if m.stuck == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s is still running after stop timed out - press K to force-kill", m.stuck.name)
+}
+
+// reconcileStuck clears the force-kill offer once the stuck container is no longer running
+func (m *Model) reconcileStuck() {
+	if m.stuck == nil {
+		return
+	}
+	// Search all containers, a filter may hide the stuck one from the list
+	for _, c := range m.allContainers {
+		if c.ID == m.stuck.id && c.State == "running" {
+			return
 		}
 	}
+	m.message = fmt.Sprintf("%s has stopped", m.stuck.name)
+	m.stuck = nil
 }
 
 // containersListChanged checks if the container list has meaningfully changed
@@ -0,0 +1,502 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rusenback/docker-monitor/internal/config"
+	"github.com/rusenback/docker-monitor/internal/docker"
+	"github.com/rusenback/docker-monitor/internal/model"
+	"github.com/rusenback/docker-monitor/internal/storage"
+)
+
+// fakeClient is a DockerClient that opens streams which never deliver
+// anything. Methods the tests do not use panic through the nil interface.
+type fakeClient struct {
+	docker.DockerClient
+	statsStreams int
+	logsSince    []time.Time // Start times of the log streams opened with a since
+	killed       []string
+}
+
+func (c *fakeClient) StreamContainerStats(id string) (<-chan *model.Stats, <-chan error, func()) {
+	c.statsStreams++
+	return make(chan *model.Stats), make(chan error), func() {}
+}
+
+func (c *fakeClient) StreamContainerLogs(id string) (<-chan model.LogEntry, <-chan error, func()) {
+	return make(chan model.LogEntry), make(chan error), func() {}
+}
+
+func (c *fakeClient) StreamContainerLogsSince(id string, since time.Time) (<-chan model.LogEntry, <-chan error, func()) {
+	c.logsSince = append(c.logsSince, since)
+	return make(chan model.LogEntry), make(chan error), func() {}
+}
+
+func (c *fakeClient) KillContainer(id string) error {
+	c.killed = append(c.killed, id)
+	return nil
+}
+
+func newTestModel(client docker.DockerClient) Model {
+	return NewModel(client, nil, &config.Config{Self: config.SelfOff})
+}
+
+func update(t *testing.T, m Model, msg any) Model {
+	t.Helper()
+	next, _ := m.Update(msg)
+	return next.(Model)
+}
+
+func TestSubSecondFlapKeepsHistory(t *testing.T) {
+	running := []model.Container{{ID: "abc", Name: "web", State: "running"}}
+	exited := []model.Container{{ID: "abc", Name: "web", State: "exited"}}
+
+	tests := []struct {
+		name string
+		flap []any // Messages between the first sample and the container running again
+	}{
+		{
+			// The stats stream ends but the list never shows the container stopped
+			name: "stream ended",
+			flap: []any{
+				statsMsg{containerID: "abc", ended: true},
+				containersMsg{containers: running},
+			},
+		},
+		{
+			name: "listed as exited",
+			flap: []any{
+				statsMsg{containerID: "abc", ended: true},
+				containersMsg{containers: exited},
+				containersMsg{containers: running},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeClient{}
+			m := newTestModel(client)
+			m = update(t, m, containersMsg{containers: running})
+			m = update(t, m, statsMsg{containerID: "abc", stats: &model.Stats{CPUPercent: 42, MemoryPercent: 10}})
+
+			for _, msg := range tt.flap {
+				m = update(t, m, msg)
+			}
+
+			if client.statsStreams != 2 {
+				t.Errorf("stats streams opened = %d, want 2", client.statsStreams)
+			}
+			if m.statsCancel == nil {
+				t.Error("stats stream was not reopened")
+			}
+			if m.currentStats == nil || m.currentStats.CPUPercent != 42 {
+				t.Errorf("current stats = %+v, want the last sample", m.currentStats)
+			}
+			if got := m.cpuHistory[len(m.cpuHistory)-1]; got != 42 {
+				t.Errorf("newest CPU history point = %v, want 42", got)
+			}
+			if got := m.memoryHistory[len(m.memoryHistory)-1]; got != 10 {
+				t.Errorf("newest memory history point = %v, want 10", got)
+			}
+		})
+	}
+}
+
+// logAt builds a stdout entry logged sec seconds after a fixed base time
+func logAt(sec int, message string) model.LogEntry {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	return model.LogEntry{Timestamp: base.Add(time.Duration(sec) * time.Second), Message: message, Stream: "stdout"}
+}
+
+// logOrder lists the messages of a buffer, with flagged entries suffixed by "!"
+func logOrder(logs []model.LogEntry) string {
+	var order []string
+	for _, entry := range logs {
+		if entry.OutOfOrder {
+			order = append(order, entry.Message+"!")
+		} else {
+			order = append(order, entry.Message)
+		}
+	}
+	return strings.Join(order, " ")
+}
+
+func TestInsertLog(t *testing.T) {
+	marker := logAt(5, "M")
+	marker.Stream = model.StreamMarker
+
+	tests := []struct {
+		name    string
+		entries []model.LogEntry
+		skip    int // Leading entries left out of the comparison
+		want    string
+	}{
+		{
+			name:    "in order",
+			entries: []model.LogEntry{logAt(1, "a"), logAt(2, "b"), logAt(3, "c")},
+			want:    "a b c",
+		},
+		{
+			name:    "late entry sorted into place",
+			entries: []model.LogEntry{logAt(1, "a"), logAt(3, "c"), logAt(2, "b")},
+			want:    "a b c",
+		},
+		{
+			// Entries with the same timestamp keep their arrival order
+			name:    "equal timestamps",
+			entries: []model.LogEntry{logAt(1, "a"), logAt(2, "b1"), logAt(3, "c"), logAt(2, "b2")},
+			want:    "a b1 b2 c",
+		},
+		{
+			name:    "never moved past a marker",
+			entries: []model.LogEntry{logAt(1, "a"), logAt(4, "d"), marker, logAt(2, "b")},
+			want:    "a d M b",
+		},
+		{
+			// Flagged entries stay where they are shown and do not block later sorting
+			name: "flagged entries keep their place",
+			entries: append(sequence(logReorderWindow+1),
+				logAt(-1, "late"), logAt(200, "y"), logAt(100, "x")),
+			skip: logReorderWindow + 1,
+			want: "late! x y",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs []model.LogEntry
+			for _, entry := range tt.entries {
+				logs = insertLog(logs, entry)
+			}
+			if got := logOrder(logs[tt.skip:]); got != tt.want {
+				t.Errorf("order = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// sequence returns n in-order entries named by their second
+func sequence(n int) []model.LogEntry {
+	logs := make([]model.LogEntry, n)
+	for i := range logs {
+		logs[i] = logAt(i, fmt.Sprint(i))
+	}
+	return logs
+}
+
+func TestInsertLogReorderWindow(t *testing.T) {
+	// Entries at even seconds leave a gap before each of them
+	var logs []model.LogEntry
+	for i := range logReorderWindow + 10 {
+		logs = insertLog(logs, logAt(2*i, fmt.Sprint(2*i)))
+	}
+	floor := len(logs) - logReorderWindow
+
+	tests := []struct {
+		name      string
+		sec       int
+		wantIndex int
+		wantFlag  bool
+	}{
+		{"inside the window", 2*(floor+5) - 1, floor + 5, false},
+		{"at the window boundary", 2*floor - 1, floor, false},
+		{"just beyond the window", 2*floor - 3, len(logs), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := insertLog(append([]model.LogEntry(nil), logs...), logAt(tt.sec, "late"))
+			if len(got) != len(logs)+1 {
+				t.Fatalf("len = %d, want %d", len(got), len(logs)+1)
+			}
+
+			index := -1
+			for i, entry := range got {
+				if entry.Message == "late" {
+					index = i
+				}
+			}
+			if index != tt.wantIndex || got[index].OutOfOrder != tt.wantFlag {
+				t.Errorf("inserted at %d (flagged %v), want %d (flagged %v)",
+					index, got[index].OutOfOrder, tt.wantIndex, tt.wantFlag)
+			}
+
+			// Only the flagged entry is marked when rendered
+			for i, entry := range got {
+				flagged := strings.Contains(styleLogEntry(entry, 80), "↶")
+				if flagged != (i == index && tt.wantFlag) {
+					t.Errorf("entry %d (%s) rendered flagged = %v", i, entry.Message, flagged)
+				}
+			}
+		})
+	}
+}
+
+func TestMarkSelf(t *testing.T) {
+	const full = "0123456789ab0123456789ab0123456789ab0123456789ab0123456789abcdef"
+
+	tests := []struct {
+		name   string
+		selfID string
+		want   []bool
+	}{
+		{"full ID", full, []bool{true, false, false}},
+		{"short ID", full[:12], []bool{true, false, false}},
+		{"longer prefix", full[:20], []bool{true, false, false}},
+		{"too short", full[:6], []bool{false, false, false}},
+		{"name", "dockermon", []bool{false, false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(nil)
+			m.selfID = tt.selfID
+			m.allContainers = []model.Container{
+				{ID: full[:12], Name: "monitor-1"},
+				{ID: "0123456789ff", Name: "other"},
+				{ID: "fedcba987654", Name: "dockermon"},
+			}
+			m.markSelf()
+
+			for i, c := range m.allContainers {
+				if c.IsSelf != tt.want[i] {
+					t.Errorf("%s marked self = %v, want %v", c.Name, c.IsSelf, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReplaySeek(t *testing.T) {
+	points := make([]storage.DataPoint, 5)
+
+	tests := []struct {
+		key  string
+		from int
+		want int
+	}{
+		// Fewer than 10 points still move one point per seek
+		{">", 0, 1},
+		{"<", 3, 2},
+		{"<", 0, 0},
+		{">", 4, 4},
+	}
+
+	for _, tt := range tests {
+		m := newTestModel(nil)
+		m.replay = &replayState{points: points, cursor: tt.from}
+		m.handleReplayKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tt.key)})
+		if m.replay.cursor != tt.want {
+			t.Errorf("%s from %d moved to %d, want %d", tt.key, tt.from, m.replay.cursor, tt.want)
+		}
+	}
+}
+
+func TestReplayEndsOnContainerChange(t *testing.T) {
+	containers := []model.Container{{ID: "aaa", Name: "a"}, {ID: "bbb", Name: "b"}}
+
+	m := newTestModel(&fakeClient{})
+	m = update(t, m, containersMsg{containers: containers})
+	m.replay = &replayState{containerID: "aaa", points: make([]storage.DataPoint, 3)}
+
+	// Refreshing the list keeps the replay of the selected container
+	m = update(t, m, containersMsg{containers: containers})
+	if m.replay == nil {
+		t.Fatal("replay ended on a list refresh")
+	}
+
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	if m.currentContainerID != "bbb" {
+		t.Fatalf("current container = %q, want bbb", m.currentContainerID)
+	}
+	if m.replay != nil {
+		t.Error("replay of the previous container is still shown")
+	}
+}
+
+func TestRestorePinsKeepsMissingPins(t *testing.T) {
+	tests := []struct {
+		name        string
+		pins        []string
+		wantCompare bool
+		wantShown   int // Pinned containers shown once "old" is back
+	}{
+		{"all present", []string{"web", "db"}, true, 2},
+		{"some missing", []string{"old", "web"}, true, 2},
+		{"all missing", []string{"old", "older"}, false, 1},
+		{"no pins", nil, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			cfg, err := config.LoadFrom(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg.Self = config.SelfOff
+			cfg.Pins = tt.pins
+
+			m := NewModel(&fakeClient{}, nil, cfg)
+			m = update(t, m, containersMsg{containers: []model.Container{
+				{ID: "aaa", Name: "web"}, {ID: "bbb", Name: "db"},
+			}})
+
+			if m.showCompare != tt.wantCompare {
+				t.Errorf("comparison view shown = %v, want %v", m.showCompare, tt.wantCompare)
+			}
+			if fmt.Sprint(m.config.Pins) != fmt.Sprint(tt.pins) {
+				t.Errorf("pins = %v, want %v", m.config.Pins, tt.pins)
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("config was saved on startup: %v", err)
+			}
+
+			// A stack that was down comes back up
+			m = update(t, m, containersMsg{containers: []model.Container{
+				{ID: "aaa", Name: "web"}, {ID: "bbb", Name: "db"}, {ID: "ccc", Name: "old"},
+			}})
+			if pinned, _ := m.pinnedContainers(); len(pinned) != tt.wantShown {
+				t.Errorf("%d pinned containers shown, want %d", len(pinned), tt.wantShown)
+			}
+		})
+	}
+}
+
+func TestFollowNewRun(t *testing.T) {
+	containers := []model.Container{
+		{ID: "aaa", Name: "web", State: "running"},
+		{ID: "bbb", Name: "db", State: "running"},
+	}
+	started := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	// selected builds a model showing logs of web, which is being followed
+	selected := func(client *fakeClient) Model {
+		m := newTestModel(client)
+		m = update(t, m, containersMsg{containers: containers})
+		m.logs = []model.LogEntry{logAt(1, "crashed")}
+		m.following = "aaa"
+		return m
+	}
+
+	t.Run("restart failed", func(t *testing.T) {
+		m := selected(&fakeClient{})
+		m = update(t, m, actionMsg{action: "follow", containerID: "aaa", name: "web", err: fmt.Errorf("no such container")})
+
+		if logOrder(m.logs) != "crashed" {
+			t.Errorf("logs = %q, want the previous run kept", logOrder(m.logs))
+		}
+		if m.following != "" {
+			t.Errorf("following = %q, want it cleared", m.following)
+		}
+	})
+
+	t.Run("restarted", func(t *testing.T) {
+		client := &fakeClient{}
+		m := selected(client)
+		m = update(t, m, actionMsg{action: "follow", containerID: "aaa", name: "web"})
+
+		if len(m.logs) != 0 {
+			t.Errorf("logs = %q, want the previous run dropped", logOrder(m.logs))
+		}
+		if m.logsCancel != nil {
+			t.Error("log stream of the previous run is still open")
+		}
+
+		m = update(t, m, followReadyMsg{containerID: "aaa", name: "web", info: &model.RunInfo{Running: true, StartedAt: started}})
+		if m.following != "" {
+			t.Errorf("following = %q, want it cleared", m.following)
+		}
+		if m.logsCancel == nil {
+			t.Fatal("log stream of the new run was not opened")
+		}
+		if len(client.logsSince) != 1 || !client.logsSince[0].Before(started) || started.Sub(client.logsSince[0]) > time.Second {
+			t.Errorf("log streams since = %v, want one starting just before %v", client.logsSince, started)
+		}
+	})
+
+	t.Run("another container selected", func(t *testing.T) {
+		m := selected(&fakeClient{})
+		m = update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+		m.logs = []model.LogEntry{logAt(1, "db ready")}
+		m = update(t, m, actionMsg{action: "follow", containerID: "aaa", name: "web"})
+
+		if logOrder(m.logs) != "db ready" {
+			t.Errorf("logs = %q, want the selected container's kept", logOrder(m.logs))
+		}
+		if m.logsCancel == nil {
+			t.Error("log stream of the selected container was closed")
+		}
+	})
+}
+
+func TestForceKillStuckContainer(t *testing.T) {
+	client := &fakeClient{}
+	m := newTestModel(client)
+	m = update(t, m, containersMsg{containers: []model.Container{{ID: "aaa", Name: "web", State: "running"}}})
+
+	timeout := fmt.Errorf("failed to stop container: %w", context.DeadlineExceeded)
+	m = update(t, m, actionMsg{action: "stop", containerID: "aaa", name: "web", err: timeout})
+	m = update(t, m, watchdogMsg{containerID: "aaa", name: "web", info: &model.RunInfo{State: "running", Running: true}})
+	if m.stuck == nil || m.stuck.id != "aaa" {
+		t.Fatalf("stuck = %+v, want web", m.stuck)
+	}
+
+	// Samples keep arriving while the container refuses to stop
+	m = update(t, m, statsMsg{containerID: "aaa", stats: &model.Stats{CPUPercent: 5}})
+	if !strings.Contains(m.message, "press K") {
+		t.Errorf("message = %q, want the force-kill offer kept", m.message)
+	}
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("K")})
+	m = next.(Model)
+	if cmd == nil {
+		t.Fatal("K did not kill the stuck container")
+	}
+	m = update(t, m, cmd())
+	if fmt.Sprint(client.killed) != "[aaa]" {
+		t.Errorf("killed = %v, want [aaa]", client.killed)
+	}
+	if m.stuck != nil {
+		t.Errorf("stuck = %+v, want the offer gone", m.stuck)
+	}
+	if m.message != "Killed: web" {
+		t.Errorf("message = %q, want the kill reported", m.message)
+	}
+}
+
+func TestTagFilterWithoutMatches(t *testing.T) {
+	client := &fakeClient{}
+	m := newTestModel(client)
+	m.config.SetTags("other", []string{"db"})
+	m = update(t, m, containersMsg{containers: []model.Container{{ID: "aaa", Name: "web", State: "running"}}})
+	m = update(t, m, statsMsg{containerID: "aaa", stats: &model.Stats{CPUPercent: 99}})
+	if m.statsCancel == nil || m.logsCancel == nil {
+		t.Fatal("streams of web were not opened")
+	}
+
+	// No listed container is tagged "db"
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	if m.tagFilter != "db" || len(m.containers) != 0 {
+		t.Fatalf("filter %q lists %d containers, want db with none", m.tagFilter, len(m.containers))
+	}
+	if m.statsCancel != nil || m.logsCancel != nil {
+		t.Error("streams of the hidden container are still open")
+	}
+	if len(m.openBreaches) != 0 || m.currentStats != nil || m.currentContainerID != "" {
+		t.Errorf("still tracking %q: %d open breaches, stats %+v", m.currentContainerID, len(m.openBreaches), m.currentStats)
+	}
+
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	if m.currentContainerID != "aaa" || m.statsCancel == nil || client.statsStreams != 2 {
+		t.Errorf("current container = %q with %d stats streams opened, want web reopened", m.currentContainerID, client.statsStreams)
+	}
+}
@@ -4,6 +4,12 @@ import "github.com/charmbracelet/lipgloss"
 
 // View renders the TUI interface
 func (m Model) View() string {
+	if m.showInspect {
+		return m.renderInspectOverlay()
+	}
+	if m.showCompare {
+		return m.renderCompareView()
+	}
 	return m.renderFourPanelView()
 }
 